@@ -0,0 +1,107 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// stepColors are the colors of the highlighted cell for each kind of step.
+var stepColors = map[string]color.Color{
+	placementStep:   color.RGBA{0xb5, 0xe8, 0xa0, 0xff},
+	eliminationStep: color.RGBA{0xff, 0xd0, 0x90, 0xff},
+	guessStep:       color.RGBA{0xff, 0xf0, 0x70, 0xff},
+	backtrackStep:   color.RGBA{0xff, 0xa0, 0xa0, 0xff},
+}
+
+// maxAnimationFrames limits the length of the animation for puzzles with a long search.
+// At 10 frames per second, the animation lasts 50 seconds.
+const maxAnimationFrames = 500
+
+// stepAnimation records the steps of the solver as frames of an animation.
+// The board holds the state of the grid as known from the steps, and saved holds the state before each guess which is not undone yet.
+// pending holds the cells changed by the propagation since the last frame, which are drawn as a single frame.
+// frames is the number of frames added, and leftOut the number of frames left out after maxAnimationFrames.
+type stepAnimation struct {
+	mutex     sync.Mutex
+	board     *datatypes.Board
+	saved     []*datatypes.Board
+	pending   map[datatypes.Position]color.Color
+	frames    int
+	leftOut   int
+	animation *render.Animation
+}
+
+// newStepAnimation creates a stepAnimation starting from the puzzle, where all the values are possible in the empty cells.
+// Each frame is shown for the delay, in 1/100 of a second.
+func newStepAnimation(puzzle *datatypes.Board, opts render.ImageOptions, delay int) *stepAnimation {
+	board := puzzle.Copy()
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if board.Value(datatypes.Position{X: i, Y: j}) == 0 {
+				board.Candidates[i][j] = datatypes.InitValue().Possible
+			}
+		}
+	}
+	a := &stepAnimation{board: board, pending: make(map[datatypes.Position]color.Color), animation: render.NewAnimation(opts, delay)}
+	a.addFrame(nil)
+	return a
+}
+
+// record updates the board for the step. The placements and eliminations of a propagation pass are drawn
+// as a single frame, before the next guess or backtrack, and a guess or a backtrack is a frame of its own.
+// The changed cells are highlighted.
+func (a *stepAnimation) record(kind string, pos datatypes.Position, val int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	switch kind {
+	case eliminationStep:
+		delete(a.board.Candidates[pos.X][pos.Y], val)
+		a.pending[pos] = stepColors[kind]
+		return
+	case guessStep:
+		a.flush()
+		a.saved = append(a.saved, a.board.Copy())
+		a.place(pos, val)
+	case backtrackStep:
+		a.flush()
+		a.board = a.saved[len(a.saved)-1]
+		a.saved = a.saved[:len(a.saved)-1]
+	default:
+		a.place(pos, val)
+		a.pending[pos] = stepColors[kind]
+		return
+	}
+	a.addFrame(map[datatypes.Position]color.Color{pos: stepColors[kind]})
+}
+
+// flush adds the frame of the cells changed by the propagation since the last frame, if any.
+func (a *stepAnimation) flush() {
+	if len(a.pending) > 0 {
+		a.addFrame(a.pending)
+		a.pending = make(map[datatypes.Position]color.Color)
+	}
+}
+
+// addFrame adds a frame of the board with the highlighted cells, unless the animation already holds maxAnimationFrames frames.
+func (a *stepAnimation) addFrame(highlights map[datatypes.Position]color.Color) {
+	if a.frames < maxAnimationFrames {
+		a.animation.Add(a.board, highlights)
+		a.frames++
+	} else {
+		a.leftOut++
+	}
+}
+
+// place sets the value as placed at the position, unless it is a given.
+func (a *stepAnimation) place(pos datatypes.Position, val int) {
+	if a.board.Givens[pos.X][pos.Y] == 0 {
+		a.board.Placed[pos.X][pos.Y] = val
+		a.board.Candidates[pos.X][pos.Y] = nil
+	}
+}
@@ -0,0 +1,49 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// book reads puzzles from the files given as arguments, or from stdin, and writes a PDF book with the puzzles
+// followed by their solutions. Each puzzle is labelled with its difficulty level.
+func book(args []string) {
+	flags := newFlagSet("book", "[file...]", "Write a PDF book with the puzzles, followed by their solutions.")
+	in := addInputFlags(flags, "line")
+	title := flags.String("title", "Sudoku", "title printed at the top of the pages of puzzles")
+	perPage := flags.Int("per-page", 4, "number of puzzles on a page")
+	answersPerPage := flags.Int("answers-per-page", 12, "number of solutions on a page of the answers")
+	output := flags.String("o", "", "file to write the PDF to, instead of stdout")
+	parseFlags(flags, args)
+	puzzles := in.read(flags.Args())
+	var bookPuzzles []render.BookPuzzle
+	for index, board := range puzzles {
+		solutions, difficultyLevel, ok := solveBoard(board, 2)
+		if !ok || len(solutions) == 0 {
+			exitWithError(exitNoSolution, fmt.Sprintf("puzzle %d has no solution", index+1), nil)
+		}
+		if len(solutions) > 1 {
+			fmt.Fprintf(os.Stderr, "warning: puzzle %d has more than one solution\n", index+1)
+		}
+		bookPuzzles = append(bookPuzzles, render.BookPuzzle{Puzzle: board, Solution: solutions[0], Label: difficultyLevel})
+	}
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			handleError("", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	opts := render.BookOptions{Title: *title, PerPage: *perPage, AnswersPerPage: *answersPerPage}
+	if err := render.PDFBook(out, bookPuzzles, opts); err != nil {
+		handleError("", err)
+	}
+}
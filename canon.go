@@ -0,0 +1,101 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// canon writes each puzzle in its canonical form. Equivalent puzzles, which only differ by swapping rows, columns,
+// bands or stacks, transposing, or relabeling the values, have the same canonical form.
+func canon(args []string) {
+	flags := newFlagSet("canon", "[file...]", "Write each puzzle in its canonical form, so that equivalent puzzles are written the same.\n"+
+		"Puzzles are equivalent if they only differ by swapping rows in a band, bands, columns in a stack, stacks,\n"+
+		"transposing, or relabeling the values. The canonical form is the smallest equivalent puzzle, read row by row.")
+	in := addInputFlags(flags, "grid")
+	to := addOutputFlag(flags, "line")
+	parseFlags(flags, args)
+	format := lookupOutputFormat(*to)
+	var puzzles []*datatypes.Board
+	for _, board := range in.read(flags.Args()) {
+		puzzles = append(puzzles, canonical(board))
+	}
+	writePuzzles(format, puzzles)
+}
+
+// lineOrders are the orders in which the rows, or the columns, of a grid can be read while keeping the bands,
+// or the stacks: the bands are permuted, and the rows of each band are permuted.
+var lineOrders = func() [][max]int {
+	permutations := [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	var orders [][max]int
+	for _, bands := range permutations {
+		for _, first := range permutations {
+			for _, second := range permutations {
+				for _, third := range permutations {
+					var order [max]int
+					for k, rows := range [][3]int{first, second, third} {
+						for u := 0; u < 3; u++ {
+							order[3*k+u] = 3*bands[k] + rows[u]
+						}
+					}
+					orders = append(orders, order)
+				}
+			}
+		}
+	}
+	return orders
+}()
+
+// canonical returns the canonical form of the givens of the board: the smallest equivalent grid of givens,
+// comparing the cells row by row with 0 for an empty cell. The values are relabeled in the order they are first read.
+func canonical(board *datatypes.Board) *datatypes.Board {
+	var transposed [max][max]int
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			transposed[i][j] = board.Givens[j][i]
+		}
+	}
+	best := [max * max]int{}
+	found := false
+	for _, grid := range [][max][max]int{board.Givens, transposed} {
+		for _, rows := range lineOrders {
+			for _, columns := range lineOrders {
+				if candidate, smaller := relabel(&grid, &rows, &columns, &best, found); smaller {
+					best, found = candidate, true
+				}
+			}
+		}
+	}
+	result := datatypes.NewBoard()
+	result.Info = board.Info
+	for k, val := range best {
+		result.Givens[k/max][k%max] = val
+	}
+	return result
+}
+
+// relabel reads the grid in the order of the rows and columns, relabeling the values in the order they are first read.
+// It stops as soon as the result is larger than best, and returns true if the result is smaller than best,
+// or if there is no best yet.
+func relabel(grid *[max][max]int, rows *[max]int, columns *[max]int, best *[max * max]int, found bool) ([max * max]int, bool) {
+	var labels [max + 1]int
+	next := 1
+	var result [max * max]int
+	smaller := !found
+	for k := range result {
+		val := grid[rows[k/max]][columns[k%max]]
+		if val > 0 && labels[val] == 0 {
+			labels[val] = next
+			next++
+		}
+		result[k] = labels[val]
+		if !smaller {
+			if result[k] > best[k] {
+				return result, false
+			}
+			smaller = result[k] < best[k]
+		}
+	}
+	return result, smaller
+}
@@ -0,0 +1,278 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+	"github.com/wittyameta/sudoku-solver/logic"
+)
+
+// command is a subcommand of the solver. run is called with the arguments after the name of the command.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands are the subcommands, in the order of the help text.
+var commands = []command{
+	{"solve", "solve a puzzle, and print the solutions", solveCommand},
+	{"count", "count the solutions of puzzles", count},
+	{"rate", "rate the difficulty of puzzles", rate},
+	{"hint", "show the next step which can be found in a puzzle", hint},
+	{"play", "play a puzzle in the terminal", play},
+	{"edit", "edit a puzzle, checking its solutions after each change", edit},
+	{"generate", "generate puzzles with a unique solution", generate},
+	{"validate", "check that puzzles are well formed, and have a unique solution", validate},
+	{"convert", "convert puzzles between file formats", convert},
+	{"canon", "write puzzles in their canonical form, to find equivalent puzzles", canon},
+	{"book", "write a printable PDF book of puzzles", book},
+	{"recognize", "read a puzzle from a screenshot", recognizeImage},
+}
+
+// Exit codes of the solver. The outcome of solving a puzzle is exitUnique, exitNoSolution, exitMultipleSolutions or exitTimeout.
+const (
+	exitUnique            = 0
+	exitError             = 1
+	exitInvalidInput      = 2
+	exitNoSolution        = 3
+	exitMultipleSolutions = 4
+	exitTimeout           = 5
+)
+
+const exitCodesHelp = `Exit codes:
+  0  the puzzles have a unique solution, or the command succeeded
+  1  error, like a file which cannot be read or written
+  2  invalid input: a puzzle or a flag which cannot be used
+  3  a puzzle has no solution
+  4  a puzzle has more than one solution
+  5  the search timed out
+With several puzzles, the exit code is the outcome of the first puzzle without a unique solution.`
+
+// exitCode is the code the program exits with, once the command is done.
+var exitCode int
+
+// setOutcome records the outcome of solving a puzzle, keeping the first outcome which is not exitUnique.
+func setOutcome(code int) {
+	if exitCode == exitUnique {
+		exitCode = code
+	}
+}
+
+// outcome returns the outcome for the number of solutions found by a search which stops at limit solutions,
+// unless limit is 0. The solutions are not all found if the search timed out, or stopped at limit solutions.
+// searchLimit gives the limit, so that a search which stops there found more solutions than are shown.
+func outcome(solutions int, limit int) int {
+	switch {
+	case timedOut:
+		return exitTimeout
+	case solutions == 0:
+		return exitNoSolution
+	case solutions > 1, limit > 0 && solutions >= limit:
+		return exitMultipleSolutions
+	}
+	return exitUnique
+}
+
+// searchLimit returns the number of solutions to search for, when up to limit solutions are shown (all if limit is 0).
+// It is one more than the limit, so that a puzzle with exactly limit solutions is told from one with more,
+// and a unique solution from several.
+func searchLimit(limit int) int {
+	if limit > 0 {
+		return limit + 1
+	}
+	return 0
+}
+
+// addSearchFlags adds the flags of a command which searches for solutions: -timeout, which sets searchTimeout, and -branching.
+func addSearchFlags(flags *flag.FlagSet) {
+	flags.DurationVar(&searchTimeout, "timeout", 0, "stop searching for solutions of a puzzle after this time, like 10s. No limit if not given")
+	flags.Var(choiceFlag{&branching, []string{branchMin, branchFirst, branchRandom}}, "branching", "cell to guess when the elimination is stuck: "+
+		branchMin+" for the cell with the fewest possible values, "+branchFirst+" for the first empty cell row by row, or "+branchRandom)
+}
+
+// addEngineFlag adds the -engine flag of a command which can solve puzzles with either engine,
+// and the -chain-length and -als-size flags which limit the chains and the almost locked sets of the logic engine.
+func addEngineFlag(flags *flag.FlagSet) {
+	flags.Var(choiceFlag{&engine, []string{backtrackingEngine, logicEngine}}, "engine", "engine which solves the puzzles: "+
+		backtrackingEngine+", or "+logicEngine+" to apply the techniques of human solvers step by step, without guessing")
+	flags.IntVar(&logic.MaxChainLength, "chain-length", logic.MaxChainLength, "with -engine "+logicEngine+", the most links of a chain")
+	flags.IntVar(&logic.MaxALSSize, "als-size", logic.MaxALSSize, "with -engine "+logicEngine+", the most cells of an almost locked set")
+}
+
+// runCommand runs the subcommand named by the first argument.
+// Without a subcommand, the arguments are the flags of solve, so that "solver < puzzle.txt" solves the puzzle.
+func runCommand(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "-help" && args[0] != "--help" {
+		solveCommand(args)
+		return
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			c.run(args[1:])
+			return
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(os.Stdout)
+		return
+	}
+	usage(os.Stderr)
+	handleInvalidInput("unknown command "+args[0], nil)
+}
+
+// usage prints the commands of the solver.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: solver <command> [flags] [file...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Puzzles are read from the files given, or from stdin. Without a command, the puzzle on stdin is solved.")
+	fmt.Fprintln(w, "Run solver <command> -h for the flags of a command.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, exitCodesHelp)
+}
+
+// newFlagSet creates the flags of a command, with a help text made of the usage line and the description.
+// Every command has the -config and -profile flags, and its flags are parsed by parseFlags.
+func newFlagSet(name string, arguments string, description string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: solver %s [flags] %s\n\n%s\n\nFlags:\n", name, arguments, description)
+		flags.PrintDefaults()
+	}
+	addConfigFlags(flags)
+	return flags
+}
+
+// puzzleInput is how a command reads puzzles: from is the name of the format, or empty to find it from the file extension,
+// with defaultFormat for stdin and for unknown extensions. Puzzles with constraints which are not supported
+// are read without them if ignoreUnsupported is set. If symbols is set, the grid and line formats are read
+// with these characters for the values 1 to 9.
+type puzzleInput struct {
+	from              string
+	defaultFormat     string
+	ignoreUnsupported bool
+	symbols           string
+}
+
+// addInputFlags adds the -from and -ignore-unsupported flags of a command which reads puzzles.
+func addInputFlags(flags *flag.FlagSet, defaultFormat string) *puzzleInput {
+	in := &puzzleInput{defaultFormat: defaultFormat}
+	flags.StringVar(&in.from, "from", "", "format of the input: "+strings.Join(formats.Names(), ", ")+
+		". The default is found from the file extension, else "+defaultFormat+". With line, each line is a puzzle")
+	flags.BoolVar(&in.ignoreUnsupported, "ignore-unsupported", false, "read puzzles with unsupported constraints, leaving out those constraints")
+	return in
+}
+
+// read reads the puzzles from the files, or from stdin if there is no file.
+func (in *puzzleInput) read(paths []string) []*datatypes.Board {
+	if len(paths) == 0 {
+		return in.readPuzzles(in.format(""), os.Stdin, "stdin")
+	}
+	var puzzles []*datatypes.Board
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			handleError("", err)
+		}
+		puzzles = append(puzzles, in.readPuzzles(in.format(path), file, path)...)
+		file.Close()
+	}
+	return puzzles
+}
+
+// readOne reads a single puzzle from the file, or from stdin if there is no file.
+func (in *puzzleInput) readOne(paths []string) *datatypes.Board {
+	if len(paths) > 1 {
+		handleInvalidInput("expected a single puzzle file", nil)
+	}
+	puzzles := in.read(paths)
+	if len(puzzles) > 1 {
+		handleInvalidInput(fmt.Sprintf("expected a single puzzle, got %d", len(puzzles)), nil)
+	}
+	return puzzles[0]
+}
+
+// format returns the format of the file at path, or of stdin if path is empty.
+func (in *puzzleInput) format(path string) formats.Format {
+	name := in.from
+	if name == "" {
+		if format, ok := formats.ForFile(path); ok && path != "" {
+			return format
+		}
+		name = in.defaultFormat
+	}
+	format, ok := formats.Lookup(name)
+	if !ok {
+		handleInvalidInput("unknown input format "+name+". Formats are: "+strings.Join(formats.Names(), ", "), nil)
+	}
+	return format
+}
+
+// readPuzzles reads the puzzles from r in the format. The line format can hold many puzzles, and the other formats a single puzzle.
+// name is the name of the input in the error messages.
+func (in *puzzleInput) readPuzzles(format formats.Format, r io.Reader, name string) []*datatypes.Board {
+	if in.symbols != "" && in.symbols != "123456789" && (format.Name == "line" || format.Name == "grid") {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			handleError("", err)
+		}
+		var replacements []string
+		for _, symbol := range in.symbols {
+			replacements = append(replacements, string(symbol), fmt.Sprint(len(replacements)/2+1))
+		}
+		r = strings.NewReader(strings.NewReplacer(replacements...).Replace(string(data)))
+	}
+	if format.Name == "line" {
+		boards, err := formats.ReadLineList(r)
+		if err != nil {
+			handleInvalidInput(name+": "+err.Error(), nil)
+		}
+		return boards
+	}
+	board, err := format.Read(r)
+	if unsupported, ok := err.(*formats.UnsupportedError); ok && in.ignoreUnsupported {
+		fmt.Fprintln(os.Stderr, "warning: "+name+": leaving out", unsupported)
+	} else if err != nil {
+		handleInvalidInput(name+": "+err.Error(), nil)
+	}
+	return []*datatypes.Board{board}
+}
+
+// addOutputFlag adds the -to flag of a command which writes puzzles, and returns the name of the format.
+func addOutputFlag(flags *flag.FlagSet, defaultFormat string) *string {
+	return flags.String("to", defaultFormat, "format of the output: "+strings.Join(formats.Names(), ", "))
+}
+
+// lookupOutputFormat returns the format with the name given to -to.
+func lookupOutputFormat(name string) formats.Format {
+	format, ok := formats.Lookup(name)
+	if !ok {
+		handleInvalidInput("unknown output format "+name+". Formats are: "+strings.Join(formats.Names(), ", "), nil)
+	}
+	return format
+}
+
+// writePuzzles writes the puzzles to stdout in the format. Puzzles in formats of several lines are separated by an empty line.
+func writePuzzles(format formats.Format, puzzles []*datatypes.Board) {
+	for index, board := range puzzles {
+		if index > 0 && format.Name != "line" {
+			fmt.Println()
+		}
+		if err := format.Write(os.Stdout, board); err != nil {
+			handleError("", err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+)
+
+const testPuzzle = "....45...8.....2.7..2.....4..6...3.2...1.....2.74..6..64..98...79...4..........3."
+
+// TestCanonical verifies that equivalent puzzles have the same canonical form.
+func TestCanonical(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	equivalent := datatypes.NewBoard()
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			// transpose, swap the first two bands, swap the last two columns of each stack, and relabel the values.
+			val := board.Givens[j][i]
+			if val > 0 {
+				val = val%max + 1
+			}
+			row, column := []int{3, 4, 5, 0, 1, 2, 6, 7, 8}[i], j-j%3+[]int{0, 2, 1}[j%3]
+			equivalent.Givens[row][column] = val
+		}
+	}
+	first, second := canonical(board), canonical(equivalent)
+	if first.Givens != second.Givens {
+		t.Error("Expected the same canonical form, got ", first.Givens, second.Givens)
+	}
+	if first.Givens[0][0] != 0 {
+		t.Error("Expected an empty first cell, got ", first.Givens[0][0])
+	}
+}
+
+// TestGeneratePuzzle verifies that a generated puzzle has a unique solution, and is the same for the same seed.
+func TestGeneratePuzzle(t *testing.T) {
+	first, ok := generatePuzzle(rand.New(rand.NewSource(1)), "", 0, 1)
+	if !ok {
+		t.Fatal("Expected a puzzle")
+	}
+	if problems, _ := validateBoard(first); problems != nil {
+		t.Error("Expected a valid puzzle, got ", problems)
+	}
+	second, _ := generatePuzzle(rand.New(rand.NewSource(1)), "", 0, 1)
+	if first.Givens != second.Givens {
+		t.Error("Expected the same puzzle for the same seed")
+	}
+}
+
+// TestNextHint verifies the hints for a repeated value, a wrong value and a single.
+func TestNextHint(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	if hint := nextHint(board); hint != "Hidden Single: r2c3 = 4, the only cell of block 1 where 4 can go." {
+		t.Error("Expected the hidden single r2c3 = 4, got ", hint)
+	}
+	board.Placed[0][0] = 3
+	if hint := nextHint(board); hint != "Mistake: r1c1 is not 3." {
+		t.Error("Expected a mistake at r1c1, got ", hint)
+	}
+	board.Placed[0][0] = 5
+	if hint := nextHint(board); hint != "Conflict: r1c1 and r1c6 are both 5" {
+		t.Error("Expected a conflict, got ", hint)
+	}
+}
+
+// TestValidateOutcome verifies the exit codes of a puzzle with a unique solution, more than one solution, and a conflict.
+func TestValidateOutcome(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	if _, code := validateBoard(board); code != exitUnique {
+		t.Error("Expected exitUnique, got ", code)
+	}
+	for i := 0; i < max; i++ {
+		board.Givens[8][i] = 0
+	}
+	if _, code := validateBoard(board); code != exitMultipleSolutions {
+		t.Error("Expected exitMultipleSolutions, got ", code)
+	}
+	board.Givens[0][0] = 5
+	if _, code := validateBoard(board); code != exitNoSolution {
+		t.Error("Expected exitNoSolution, got ", code)
+	}
+}
+
+// captureOutput returns what run prints to stdout.
+func captureOutput(t *testing.T, run func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		var out bytes.Buffer
+		out.ReadFrom(r)
+		output <- out.String()
+	}()
+	run()
+	os.Stdout = stdout
+	w.Close()
+	return <-output
+}
+
+// TestSolutionLimit verifies that a search which stops at one solution of a puzzle with several solutions
+// prints 1+, and exits with exitMultipleSolutions, and that a puzzle with exactly as many solutions as the limit prints their number.
+func TestSolutionLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzle.txt")
+	os.WriteFile(path, []byte(testPuzzle[:72]+strings.Repeat(".", max)), 0644)
+	defer func(previous func(grid *datatypes.Grid, iteration int)) { onSolution, exitCode = previous, exitUnique }(onSolution)
+	exitCode = exitUnique
+	if out := captureOutput(t, func() { count([]string{"-from", "line", "-limit", "1", path}) }); out != "1+\n" {
+		t.Error("Expected 1+, got ", out)
+	}
+	if exitCode != exitMultipleSolutions {
+		t.Error("Expected exitMultipleSolutions, got ", exitCode)
+	}
+	exitCode = exitUnique
+	out := captureOutput(t, func() { solveCommand([]string{"-from", "line", "-max-solutions", "1", path}) })
+	if !strings.Contains(out, "Total solutions: 1+\n") || exitCode != exitMultipleSolutions {
+		t.Error("Expected 1+ solutions and exitMultipleSolutions, got ", exitCode, "\n", out)
+	}
+	// a deadly rectangle of the solution is left empty, so the puzzle has exactly 2 solutions.
+	os.WriteFile(path, []byte("17924586386491325735278619491.8.734243.1.2978287439615643598721791324586528671439"), 0644)
+	if out := captureOutput(t, func() { count([]string{"-from", "line", "-limit", "2", path}) }); out != "2\n" {
+		t.Error("Expected 2, got ", out)
+	}
+	out = captureOutput(t, func() { solveCommand([]string{"-from", "line", "-max-solutions", "2", path}) })
+	if !strings.Contains(out, "Total solutions: 2\n") {
+		t.Error("Expected 2 solutions, got\n", out)
+	}
+}
+
+// TestGame verifies moving the cursor, entering values and pencil marks, the conflicts, and undo and redo.
+func TestGame(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	g := newGame(board)
+	for _, key := range parseKeys([]byte("\x1b[Ajj3p12p")) {
+		g.handleKey(key)
+	}
+	if g.cursor != (datatypes.Position{X: 1, Y: 0}) || g.board.Placed[1][0] != 0 {
+		t.Error("Expected the cursor on the given r2c1, got ", g.cursor, g.message)
+	}
+	for _, key := range parseKeys([]byte("l\x1b[C4p12p")) {
+		g.handleKey(key)
+	}
+	if g.board.Placed[1][2] != 4 || len(conflictCells(g.board)) != 0 {
+		t.Error("Expected 4 in r2c3 without conflicts, got ", g.board.Placed[1][2])
+	}
+	g.handleKey("8")
+	if !conflictCells(g.board)[datatypes.Position{X: 1, Y: 2}] {
+		t.Error("Expected a conflict of 8 in r2c3")
+	}
+	g.handleKey("u")
+	g.handleKey("u")
+	if g.board.Placed[1][2] != 0 {
+		t.Error("Expected r2c3 to be empty after undo, got ", g.board.Placed[1][2])
+	}
+	g.handleKey("r")
+	if g.board.Placed[1][2] != 4 {
+		t.Error("Expected 4 in r2c3 after redo, got ", g.board.Placed[1][2])
+	}
+	g.handleKey("s")
+	if !g.solved() {
+		t.Error("Expected the puzzle to be solved")
+	}
+}
+
+// TestSetter verifies the status after set, clear and undo, count with a limit of 1 and with the default limit, and the errors of the commands.
+func TestSetter(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	s := &setter{board: board, limit: 10}
+	var out bytes.Buffer
+	s.run(strings.NewReader("clear r1c5\ncount 1\ncount\nset r1c5 4\nset r1c1 5\nundo\nset r10c1 4\ncount 1\nquit\nshow\n"), &out, false)
+	lines := strings.Split(out.String(), "\n")
+	expected := []string{
+		"22 givens, 10+ solutions. Propagation solves 8 of 59 empty cells.",
+		"1+ solutions",
+		"10+ solutions",
+		"23 givens, unique solution. Propagation solves 21 of 58 empty cells.",
+		"24 givens. Conflict: r1c1 and r1c6 are both 5.",
+		"23 givens, unique solution. Propagation solves 21 of 58 empty cells.",
+		"error: expected a cell like r3c5, got r10c1",
+		"unique solution",
+		"",
+	}
+	if len(lines) != len(expected) {
+		t.Fatal("Expected a line for each command, got\n", out.String())
+	}
+	for k, line := range lines {
+		if line != expected[k] {
+			t.Error("Expected "+expected[k]+", got ", line)
+		}
+	}
+}
+
+// TestCancelSearch verifies that a search of an empty grid, which would not end, stops when it is cancelled.
+func TestCancelSearch(t *testing.T) {
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreInt32(&cancelSearch, 1)
+	}()
+	searchBoard(datatypes.NewBoard(), 0, func(grid *datatypes.Grid, iteration int) {})
+	atomic.StoreInt32(&cancelSearch, 0)
+	if numSolutions == 0 {
+		t.Error("Expected solutions before the search is cancelled")
+	}
+}
+
+// TestSolveWatched verifies the output for a puzzle, and the position of a parse error.
+func TestSolveWatched(t *testing.T) {
+	format, _ := formats.Lookup("line")
+	var out bytes.Buffer
+	solveWatched(&out, "puzzle.txt", format, []byte(testPuzzle))
+	if !strings.Contains(out.String(), "Total solutions: 1\nDifficulty level: hard\n") {
+		t.Error("Expected a unique solution, got\n", out.String())
+	}
+	out.Reset()
+	grid, _ := formats.Lookup("grid")
+	solveWatched(&out, "puzzle.txt", grid, []byte("_ _ _ _ 4 5 _ _ _\n8 _ x _ _ _ 2 _ 7\n"))
+	if !strings.Contains(out.String(), "error: puzzle.txt: line 2, column 5") {
+		t.Error("Expected an error at line 2, column 5, got\n", out.String())
+	}
+}
+
+// TestParseFlags verifies that the settings of a profile are the defaults of the flags, and that a flag given on the command line is kept.
+func TestParseFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"profiles": {"fast": {"timeout": "2s", "branching": "first", "output": "svg"}}}`), 0644)
+	defer func() { searchTimeout, branching = 0, branchMin }()
+	flags := newFlagSet("test", "", "")
+	addSearchFlags(flags)
+	parseFlags(flags, []string{"-config", path, "-profile", "fast", "-timeout", "1s"})
+	if searchTimeout != time.Second || branching != branchFirst {
+		t.Error("Expected a timeout of 1s and first branching, got ", searchTimeout, branching)
+	}
+	if _, err := loadProfile(path, "slow"); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+}
+
+// TestBranching verifies that the puzzle has the same solution with each branching strategy.
+func TestBranching(t *testing.T) {
+	board, _ := formats.ReadLine(strings.NewReader(testPuzzle))
+	defer func() { branching = branchMin }()
+	expected, _, _ := solveBoard(board, 0)
+	for _, branching = range []string{branchFirst, branchRandom} {
+		solutions, _, _ := solveBoard(board, 0)
+		if len(solutions) != 1 || solutions[0].Placed != expected[0].Placed {
+			t.Error("Expected the same solution with branching "+branching+", got ", len(solutions))
+		}
+	}
+}
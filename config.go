@@ -0,0 +1,149 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// configEnv is the environment variable with the path of the config file, used when -config is not given.
+const configEnv = "SUDOKU_SOLVER_CONFIG"
+
+// defaultProfile is the profile used when -profile is not given, if the config file has it.
+const defaultProfile = "default"
+
+// profileSettings are the settings a profile can hold. Each setting is the default of the flag with the same name.
+var profileSettings = []string{"engine", "branching", "max-solutions", "timeout", "output", "symbols", "chain-length", "als-size"}
+
+// config is the JSON config file: named profiles, each with settings like {"timeout": "10s", "max-solutions": 2}.
+type config struct {
+	Profiles map[string]map[string]interface{} `json:"profiles"`
+}
+
+// addConfigFlags adds the -config and -profile flags, which every command has.
+func addConfigFlags(flags *flag.FlagSet) {
+	flags.String("config", "", "path of the JSON config file with the profiles. The default is $"+configEnv+
+		", else sudoku-solver/config.json in $XDG_CONFIG_HOME or ~/.config")
+	flags.String("profile", "", "profile of the config file with the default settings. The profile "+defaultProfile+" is used if not given")
+}
+
+// parseFlags parses the flags of a command, and then sets the flags which are not given on the command line
+// from the settings of the profile.
+func parseFlags(flags *flag.FlagSet, args []string) {
+	flags.Parse(args)
+	path, name := flags.Lookup("config").Value.String(), flags.Lookup("profile").Value.String()
+	profile, err := loadProfile(path, name)
+	if err != nil {
+		handleInvalidInput("", err)
+	}
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	var settings []string
+	for setting := range profile {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+	for _, setting := range settings {
+		if !isProfileSetting(setting) {
+			handleInvalidInput(fmt.Sprintf("profile %s: unknown setting %s. Settings are: %s", name, setting, strings.Join(profileSettings, ", ")), nil)
+		}
+		if flags.Lookup(setting) == nil || given[setting] {
+			continue
+		}
+		if err := flags.Set(setting, fmt.Sprint(profile[setting])); err != nil {
+			handleInvalidInput(fmt.Sprintf("profile %s: %s: %s", name, setting, err), nil)
+		}
+	}
+}
+
+// loadProfile returns the settings of the profile with the name, from the config file at path.
+// The path and the name are found as described by addConfigFlags if they are empty.
+// Returns no settings if there is no config file, and no profile is asked for.
+func loadProfile(path string, name string) (map[string]interface{}, error) {
+	if path == "" {
+		path = configPath()
+	}
+	if path == "" {
+		if name != "" {
+			return nil, fmt.Errorf("profile %s: no config file found", name)
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	if name == "" {
+		return c.Profiles[defaultProfile], nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: no profile %s", path, name)
+	}
+	return profile, nil
+}
+
+// configPath returns the path of the config file from the environment, or from the XDG config directory if the file exists there.
+// Returns an empty path if there is no config file.
+func configPath() string {
+	if path := os.Getenv(configEnv); path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	path := filepath.Join(dir, "sudoku-solver", "config.json")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// isProfileSetting returns true if the name is one of profileSettings.
+func isProfileSetting(name string) bool {
+	for _, setting := range profileSettings {
+		if setting == name {
+			return true
+		}
+	}
+	return false
+}
+
+// choiceFlag is a flag whose value is one of the choices.
+type choiceFlag struct {
+	value   *string
+	choices []string
+}
+
+func (c choiceFlag) String() string {
+	if c.value == nil {
+		return ""
+	}
+	return *c.value
+}
+
+func (c choiceFlag) Set(s string) error {
+	for _, choice := range c.choices {
+		if s == choice {
+			*c.value = s
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of %s, got %s", strings.Join(c.choices, ", "), s)
+}
@@ -0,0 +1,15 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+// convert reads puzzles in one format, and writes them to stdout in another format.
+// Puzzles with constraints which are not supported are converted without them only if -ignore-unsupported is given.
+func convert(args []string) {
+	flags := newFlagSet("convert", "[file...]", "Convert puzzles between file formats.")
+	in := addInputFlags(flags, "grid")
+	to := addOutputFlag(flags, "grid")
+	parseFlags(flags, args)
+	outFormat := lookupOutputFormat(*to)
+	writePuzzles(outFormat, in.read(flags.Args()))
+}
@@ -0,0 +1,66 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/logic"
+)
+
+// count prints the number of solutions of each puzzle, on a line for each puzzle.
+func count(args []string) {
+	flags := newFlagSet("count", "[file...]", "Print the number of solutions of each puzzle, on a line for each puzzle.\n"+
+		"A puzzle whose search timed out is printed as timeout.\n\n"+exitCodesHelp)
+	in := addInputFlags(flags, "grid")
+	limit := flags.Int("limit", 1000, "count up to this number of solutions, which is printed followed by + if there are more. 0 counts all the solutions")
+	addSearchFlags(flags)
+	parseFlags(flags, args)
+	for _, board := range in.read(flags.Args()) {
+		searchBoard(board, searchLimit(*limit), func(grid *datatypes.Grid, iteration int) {})
+		setOutcome(outcome(numSolutions, searchLimit(*limit)))
+		switch {
+		case timedOut:
+			fmt.Println("timeout")
+		case *limit > 0 && numSolutions >= searchLimit(*limit):
+			fmt.Printf("%d+\n", *limit)
+		default:
+			fmt.Println(numSolutions)
+		}
+	}
+}
+
+// rate prints the difficulty level of each puzzle, on a line for each puzzle.
+// Puzzles which do not have a unique solution are rated as invalid.
+func rate(args []string) {
+	flags := newFlagSet("rate", "[file...]", "Print the difficulty level of each puzzle (easy, medium or hard), on a line for each puzzle.\n"+
+		"Puzzles which do not have a unique solution are rated as invalid. With -engine logic, the level is the level\n"+
+		"of the hardest technique needed (easy, medium, hard or expert), or unrated if the techniques are not enough.\n\n"+exitCodesHelp)
+	in := addInputFlags(flags, "grid")
+	addSearchFlags(flags)
+	addEngineFlag(flags)
+	parseFlags(flags, args)
+	for _, board := range in.read(flags.Args()) {
+		solutions, level, _ := solveBoard(board, 2)
+		code := outcome(len(solutions), 2)
+		setOutcome(code)
+		if code == exitUnique && engine == logicEngine {
+			level = "unrated"
+			if result := logic.Solve(board, logic.Techniques); result.Solved {
+				level = result.Level
+			}
+		}
+		switch code {
+		case exitUnique:
+			fmt.Println(level)
+		case exitNoSolution:
+			fmt.Println("invalid: no solution")
+		case exitMultipleSolutions:
+			fmt.Println("invalid: more than one solution")
+		default:
+			fmt.Println("timeout")
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package datatypes
+
+import "sort"
+
+// Board is a sudoku puzzle as it is read from, or written to, a file.
+// Givens are the values of the puzzle, and Placed are the values entered later, by a player or by the solver.
+// A value of 0 means that the cell is empty.
+// Candidates contains the pencil marks of each cell. The map is nil if no pencil marks are recorded for the cell.
+type Board struct {
+	Givens     [9][9]int
+	Placed     [9][9]int
+	Candidates [9][9]map[int]bool
+	Info       Info
+}
+
+// Info contains the descriptive headers of a puzzle. Each field is empty if it is not known.
+type Info struct {
+	Title       string
+	Author      string
+	Description string
+	Comment     string
+	Date        string
+	Source      string
+	Level       string
+	URL         string
+}
+
+// NewBoard creates an empty Board.
+func NewBoard() *Board {
+	return &Board{}
+}
+
+// Copy creates a copy of the board, with its own maps of candidates.
+func (board *Board) Copy() *Board {
+	copied := *board
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if board.Candidates[i][j] != nil {
+				copied.Candidates[i][j] = make(map[int]bool)
+				for val, ok := range board.Candidates[i][j] {
+					copied.Candidates[i][j][val] = ok
+				}
+			}
+		}
+	}
+	return &copied
+}
+
+// Value returns the given or placed value at the position, or 0 if the cell is empty.
+func (board *Board) Value(pos Position) int {
+	if board.Givens[pos.X][pos.Y] > 0 {
+		return board.Givens[pos.X][pos.Y]
+	}
+	return board.Placed[pos.X][pos.Y]
+}
+
+// CountGivens returns the number of given values, and the number of distinct given values.
+func (board *Board) CountGivens() (count int, distinct int) {
+	values := make(map[int]bool)
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if board.Givens[i][j] > 0 {
+				count++
+				values[board.Givens[i][j]] = true
+			}
+		}
+	}
+	return count, len(values)
+}
+
+// Grid creates a Grid where the given and placed values of the board are set.
+// Returns the grid, and the number of values which are set.
+func (board *Board) Grid() (*Grid, int) {
+	grid := InitGrid()
+	count := 0
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			val := board.Value(Position{X: i, Y: j})
+			if val > 0 {
+				grid[i][j].IterationValues[0] = *SetValue(val)
+				*grid[i][j].Val = val
+				count++
+			}
+		}
+	}
+	return grid, count
+}
+
+// PeerCandidates returns the values which are not given or placed in the row, column or block of the position.
+func (board *Board) PeerCandidates(pos Position) map[int]bool {
+	possible := InitValue().Possible
+	blockX, blockY := pos.X-pos.X%3, pos.Y-pos.Y%3
+	for k := 0; k < 9; k++ {
+		delete(possible, board.Value(Position{X: pos.X, Y: k}))
+		delete(possible, board.Value(Position{X: k, Y: pos.Y}))
+		delete(possible, board.Value(Position{X: blockX + k/3, Y: blockY + k%3}))
+	}
+	return possible
+}
+
+// SortedValues returns the keys of a map of possible values, in increasing order.
+func SortedValues(possible map[int]bool) []int {
+	var values []int
+	for val, ok := range possible {
+		if ok {
+			values = append(values, val)
+		}
+	}
+	sort.Ints(values)
+	return values
+}
+
+// BoardFromGrid creates a Board from the values of the grid for the given iteration.
+// Values which are given in the puzzle are set as givens, and the other values as placed values.
+// If puzzle is nil, all the values of the grid are set as givens.
+// The possible values of the cells which are not set are copied as candidates.
+func BoardFromGrid(grid *Grid, iteration int, puzzle *Board) *Board {
+	board := NewBoard()
+	if puzzle != nil {
+		board.Info = puzzle.Info
+	}
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			cell := &grid[i][j]
+			val := *cell.Val
+			switch {
+			case val == 0:
+				if value, ok := cell.IterationValues[iteration]; ok {
+					board.Candidates[i][j] = CopyValue(value).Possible
+				}
+			case puzzle == nil || puzzle.Givens[i][j] == val:
+				board.Givens[i][j] = val
+			default:
+				board.Placed[i][j] = val
+			}
+		}
+	}
+	return board
+}
@@ -16,6 +16,18 @@ type Position struct {
 	Y int
 }
 
+// Symbols are the characters printed by Print and PrintCandidates for the values 1 to 9.
+var Symbols = "123456789"
+
+// Symbol returns the character of Symbols for the value, or 0 for an empty cell.
+func Symbol(val int) string {
+	symbols := []rune(Symbols)
+	if val < 1 || val > len(symbols) {
+		return fmt.Sprint(val)
+	}
+	return string(symbols[val-1])
+}
+
 // Value contains a pointer to integer value, and a map of possible values. The key for 'Possible' map can be from 1-9.
 // Val points to 0 if the value is not finalized yet, else it points to the exact value.
 type Value struct {
@@ -93,9 +105,55 @@ func (grid *Grid) Print() {
 	fmt.Println()
 	for i := 0; i < 9; i++ {
 		for j := 0; j < 9; j++ {
-			fmt.Printf("%d ", *grid[i][j].Val)
+			fmt.Print(Symbol(*grid[i][j].Val), " ")
 		}
 		fmt.Println()
 	}
 	fmt.Println()
 }
+
+// PrintCandidates prints the possible values of every cell for the given iteration.
+// Each cell is shown as a 3x3 mini-grid where the digit d is at row (d-1)/3 and column (d-1)%3, and '.' marks an eliminated digit.
+// Cells with a finalized value show only that value, in the center of the mini-grid.
+func (grid *Grid) PrintCandidates(iteration int) {
+	border := "+-------------+-------------+-------------+"
+	fmt.Println()
+	for i := 0; i < 9; i++ {
+		if i%3 == 0 {
+			fmt.Println(border)
+		} else {
+			fmt.Println("|             |             |             |")
+		}
+		for miniRow := 0; miniRow < 3; miniRow++ {
+			for j := 0; j < 9; j++ {
+				if j%3 == 0 {
+					fmt.Print("| ")
+				}
+				fmt.Print(grid.candidateRow(i, j, iteration, miniRow), " ")
+			}
+			fmt.Println("|")
+		}
+	}
+	fmt.Println(border)
+	fmt.Println()
+}
+
+// candidateRow returns the 3 characters of the mini-grid row for the cell at {i, j}.
+func (grid *Grid) candidateRow(i int, j int, iteration int, miniRow int) string {
+	cell := &grid[i][j]
+	if *cell.Val > 0 {
+		if miniRow == 1 {
+			return " " + Symbol(*cell.Val) + " "
+		}
+		return "   "
+	}
+	row := ""
+	for d := miniRow*3 + 1; d <= miniRow*3+3; d++ {
+		if cell.IterationValues[iteration].Possible[d] {
+			row += Symbol(d)
+		} else {
+			row += "."
+		}
+	}
+	return row
+}
@@ -0,0 +1,47 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package datatypes
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestPrintCandidates verifies the 3x3 mini-grid of a cell with a value, and of a cell with eliminated digits.
+func TestPrintCandidates(t *testing.T) {
+	grid := InitGrid()
+	*grid[0][0].Val = 5
+	delete(grid[0][1].IterationValues[0].Possible, 2)
+	delete(grid[0][1].IterationValues[0].Possible, 9)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	grid.PrintCandidates(0)
+	os.Stdout = stdout
+	w.Close()
+	var out bytes.Buffer
+	out.ReadFrom(r)
+	lines := strings.Split(out.String(), "\n")
+	expected := []string{
+		"",
+		"+-------------+-------------+-------------+",
+		"|     1.3 123 | 123 123 123 | 123 123 123 |",
+		"|  5  456 456 | 456 456 456 | 456 456 456 |",
+		"|     78. 789 | 789 789 789 | 789 789 789 |",
+		"|             |             |             |",
+	}
+	if len(lines) != 40 {
+		t.Fatal("Expected 40 lines, got ", len(lines))
+	}
+	for k, line := range expected {
+		if lines[k] != line {
+			t.Error("Expected "+line+", got ", lines[k])
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+const editCommandsHelp = `Commands:
+  set r3c5 7     set the given of a cell
+  clear r3c5     clear the given of a cell
+  undo           undo the last set or clear
+  show           print the puzzle
+  candidates     print the possible values left by propagation
+  count [limit]  count the solutions, up to limit, or -limit if not given. 0 counts all the solutions
+  save file      save the puzzle, in the format of the file extension
+  help           print the commands
+  quit           stop editing`
+
+// edit lets a setter change a puzzle with commands read from stdin, and reports the solutions after each change.
+func edit(args []string) {
+	flags := newFlagSet("edit", "[file]", "Edit a puzzle with commands read from stdin, one on each line. The puzzle is read from the file,\n"+
+		"or is empty if no file is given. After each change, the number of solutions and the number of cells\n"+
+		"solved by propagation alone are printed.\n\n"+editCommandsHelp)
+	in := addInputFlags(flags, "grid")
+	limit := flags.Int("limit", 100, "stop counting the solutions after each change at this number")
+	addSearchFlags(flags)
+	parseFlags(flags, args)
+	board := datatypes.NewBoard()
+	if flags.NArg() > 0 {
+		board = in.readOne(flags.Args())
+	}
+	s := &setter{board: board, limit: *limit}
+	s.run(os.Stdin, os.Stdout, render.IsTerminal(os.Stdout))
+}
+
+// setter is the state of a puzzle being edited. undo holds the boards before each change.
+type setter struct {
+	board *datatypes.Board
+	limit int
+	undo  []*datatypes.Board
+}
+
+// run executes the commands read from r, and writes their output to w, until r ends or quit is read.
+// A prompt is written before each command if prompt is set.
+func (s *setter) run(r io.Reader, w io.Writer, prompt bool) {
+	scanner := bufio.NewScanner(r)
+	for {
+		if prompt {
+			fmt.Fprint(w, "> ")
+		}
+		if !scanner.Scan() {
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return
+		}
+		if err := s.execute(w, fields[0], fields[1:]); err != nil {
+			fmt.Fprintln(w, "error:", err)
+		}
+	}
+}
+
+// execute runs the command with its arguments.
+func (s *setter) execute(w io.Writer, name string, args []string) error {
+	switch name {
+	case "set", "clear":
+		if len(args) != 1 && name == "clear" || len(args) != 2 && name == "set" {
+			return fmt.Errorf("usage: %s", map[string]string{"set": "set r3c5 7", "clear": "clear r3c5"}[name])
+		}
+		pos, err := parseCellName(args[0])
+		if err != nil {
+			return err
+		}
+		val := 0
+		if name == "set" {
+			if val, err = strconv.Atoi(args[1]); err != nil || val < 1 || val > max {
+				return errors.New("expected a value from 1 to 9, got " + args[1])
+			}
+		}
+		s.undo = append(s.undo, s.board.Copy())
+		s.board.Givens[pos.X][pos.Y] = val
+		s.board.Placed[pos.X][pos.Y] = 0
+		fmt.Fprintln(w, s.status())
+	case "undo":
+		if len(s.undo) == 0 {
+			return errors.New("nothing to undo")
+		}
+		s.board = s.undo[len(s.undo)-1]
+		s.undo = s.undo[:len(s.undo)-1]
+		fmt.Fprintln(w, s.status())
+	case "show":
+		return render.Text(w, s.board, render.DefaultTextOptions(w))
+	case "candidates":
+		grid, count := s.board.Grid()
+		if _, conflict := solve(grid, count); conflict {
+			return errors.New("propagation finds a conflict")
+		}
+		opts := render.DefaultTextOptions(w)
+		opts.Candidates = true
+		return render.Text(w, datatypes.BoardFromGrid(grid, 0, s.board), opts)
+	case "count":
+		limit := s.limit
+		if len(args) > 0 {
+			var err error
+			if limit, err = strconv.Atoi(args[0]); err != nil || limit < 0 {
+				return errors.New("expected a limit, got " + args[0])
+			}
+		}
+		fmt.Fprintln(w, s.countSolutions(limit))
+	case "save":
+		if len(args) != 1 {
+			return errors.New("usage: save file")
+		}
+		return s.save(args[0])
+	case "help":
+		fmt.Fprintln(w, editCommandsHelp)
+	default:
+		return errors.New("unknown command " + name + ", try help")
+	}
+	return nil
+}
+
+// status returns the number of givens, the number of solutions up to the limit, and the number of cells solved by propagation.
+func (s *setter) status() string {
+	givens, _ := s.board.CountGivens()
+	if problems := conflicts(s.board); len(problems) > 0 {
+		return fmt.Sprintf("%d givens. Conflict: %s.", givens, problems[0])
+	}
+	grid, count := s.board.Grid()
+	positions, conflict := solve(grid, count)
+	propagation := "Propagation finds a conflict."
+	if !conflict {
+		empty := max*max - count
+		propagation = fmt.Sprintf("Propagation solves %d of %d empty cells.", empty-len(positions), empty)
+	}
+	return fmt.Sprintf("%d givens, %s. %s", givens, s.countSolutions(s.limit), propagation)
+}
+
+// countSolutions returns the number of solutions up to the limit, or all of them if limit is 0.
+func (s *setter) countSolutions(limit int) string {
+	searchBoard(s.board, searchLimit(limit), func(grid *datatypes.Grid, iteration int) {})
+	switch {
+	case timedOut:
+		return fmt.Sprintf("timed out after %d solutions", numSolutions)
+	case limit > 0 && numSolutions >= searchLimit(limit):
+		return fmt.Sprintf("%d+ solutions", limit)
+	case numSolutions == 0:
+		return "no solution"
+	case numSolutions == 1:
+		return "unique solution"
+	}
+	return fmt.Sprintf("%d solutions", numSolutions)
+}
+
+// save writes the puzzle to the file, in the format of its extension, or the grid format for an unknown extension.
+func (s *setter) save(path string) error {
+	format, ok := formats.ForFile(path)
+	if !ok {
+		format, _ = formats.Lookup("grid")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := format.Write(file, s.board); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// parseCellName returns the position of a cell named by row and column, like r3c5.
+func parseCellName(name string) (datatypes.Position, error) {
+	name = strings.ToLower(name)
+	if len(name) != 4 || name[0] != 'r' || name[2] != 'c' || name[1] < '1' || name[1] > '9' || name[3] < '1' || name[3] > '9' {
+		return datatypes.Position{}, errors.New("expected a cell like r3c5, got " + name)
+	}
+	return datatypes.Position{X: int(name[1] - '1'), Y: int(name[3] - '1')}, nil
+}
@@ -0,0 +1,256 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// candidateGridStyle gives the borders of a candidate grid, as written by HoDoKu or by Sudoku Explainer.
+// Top and bottom are the corner characters of the outer border, or empty if there is no outer border.
+type candidateGridStyle struct {
+	top, middle, bottom [3]string
+	edge                string
+}
+
+var hodokuStyle = candidateGridStyle{
+	top:    [3]string{".", ".", "."},
+	middle: [3]string{":", "+", ":"},
+	bottom: [3]string{"'", "'", "'"},
+	edge:   "|",
+}
+
+var explainerStyle = candidateGridStyle{
+	middle: [3]string{"", "+", ""},
+}
+
+// ReadHoDoKu reads a position as shared by HoDoKu.
+// Accepts a candidate grid with box borders, a grid of givens, or a single line of 81 cells.
+// A digit prefixed by '+' is a placed value. Any other single digit is a given, and a list of digits gives the candidates of an empty cell.
+func ReadHoDoKu(r io.Reader) (*datatypes.Board, error) {
+	return readCandidateGrid(r)
+}
+
+// WriteHoDoKu writes the board as a HoDoKu candidate grid, with placed values prefixed by '+'.
+// An empty cell without pencil marks is written with the values not used by its peers, and a cell with a single candidate as '.'.
+func WriteHoDoKu(w io.Writer, board *datatypes.Board) error {
+	return writeCandidateGrid(w, board, hodokuStyle)
+}
+
+// ReadExplainer reads a position as shared by Sudoku Explainer. The layouts accepted are the same as for ReadHoDoKu.
+func ReadExplainer(r io.Reader) (*datatypes.Board, error) {
+	return readCandidateGrid(r)
+}
+
+// WriteExplainer writes the board as a Sudoku Explainer candidate grid, with placed values prefixed by '+'.
+// An empty cell without pencil marks is written with the values not used by its peers, and a cell with a single candidate as '.'.
+func WriteExplainer(w io.Writer, board *datatypes.Board) error {
+	return writeCandidateGrid(w, board, explainerStyle)
+}
+
+// gridCell is a cell as read from a candidate grid, with the line and column where it starts.
+type gridCell struct {
+	token  string
+	line   int
+	column int
+}
+
+// readCandidateGrid reads the cells of a candidate grid, ignoring the lines which only contain borders.
+// A line with 9 space separated tokens has a cell per token, and any other line has a cell per character.
+func readCandidateGrid(r io.Reader) (*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var cells []gridCell
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" || isBorderLine(line) {
+			continue
+		}
+		lineCells := tokenCells(line, index+1)
+		if len(lineCells) != max {
+			lineCells, err = packedCells(line, index+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cells = append(cells, lineCells...)
+	}
+	if len(cells) != max*max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 81 cells, got %d", len(cells))}
+	}
+	board := datatypes.NewBoard()
+	for index, cell := range cells {
+		if err := setGridCell(board, index/max, index%max, cell); err != nil {
+			return nil, err
+		}
+	}
+	return board, nil
+}
+
+// isBorderLine returns true if the line is a horizontal border, made of '-' or '=' and the corner characters.
+func isBorderLine(line string) bool {
+	return strings.ContainsAny(line, "-=") && strings.Trim(line, ".:'-+*|= \t") == ""
+}
+
+// tokenCells splits the line into cells at spaces and box borders.
+func tokenCells(line string, lineNum int) []gridCell {
+	var cells []gridCell
+	for start := 0; start < len(line); {
+		if isGridSeparator(line[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(line) && !isGridSeparator(line[end]) {
+			end++
+		}
+		cells = append(cells, gridCell{line[start:end], lineNum, start + 1})
+		start = end
+	}
+	return cells
+}
+
+// packedCells splits the line into cells of one character each, and a '+' is kept with the digit after it.
+func packedCells(line string, lineNum int) ([]gridCell, error) {
+	var cells []gridCell
+	for i := 0; i < len(line); i++ {
+		if isGridSeparator(line[i]) {
+			continue
+		}
+		if line[i] == '+' {
+			if i+1 == len(line) {
+				return nil, &ParseError{lineNum, i + 1, "'+' must be followed by a digit"}
+			}
+			cells = append(cells, gridCell{line[i : i+2], lineNum, i + 1})
+			i++
+			continue
+		}
+		cells = append(cells, gridCell{line[i : i+1], lineNum, i + 1})
+	}
+	return cells, nil
+}
+
+// isGridSeparator returns true for the characters between the cells of a candidate grid.
+func isGridSeparator(c byte) bool {
+	return c == ' ' || c == '\t' || c == '|'
+}
+
+// setGridCell sets the cell at {i, j} of the board from its token.
+func setGridCell(board *datatypes.Board, i int, j int, cell gridCell) error {
+	token := cell.token
+	if token == "." || token == "0" {
+		return nil
+	}
+	placed := strings.HasPrefix(token, "+")
+	if placed {
+		token = token[1:]
+	}
+	candidates := make(map[int]bool)
+	for k := 0; k < len(token); k++ {
+		if token[k] < '1' || token[k] > '9' {
+			return &ParseError{cell.line, cell.column, fmt.Sprintf("unexpected cell %q", cell.token)}
+		}
+		candidates[int(token[k]-'0')] = true
+	}
+	switch {
+	case len(token) == 0 || (placed && len(token) > 1):
+		return &ParseError{cell.line, cell.column, fmt.Sprintf("unexpected cell %q", cell.token)}
+	case placed:
+		board.Placed[i][j] = int(token[0] - '0')
+	case len(token) == 1:
+		board.Givens[i][j] = int(token[0] - '0')
+	default:
+		board.Candidates[i][j] = candidates
+	}
+	return nil
+}
+
+// writeCandidateGrid writes the board as a candidate grid with the borders of the style.
+// Each column is as wide as its widest cell.
+func writeCandidateGrid(w io.Writer, board *datatypes.Board, style candidateGridStyle) error {
+	var cells [max][max]string
+	var widths [max]int
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			cells[i][j] = candidateToken(board, i, j)
+			if len(cells[i][j]) > widths[j] {
+				widths[j] = len(cells[i][j])
+			}
+		}
+	}
+	out := ""
+	if style.top[0] != "" {
+		out += borderLine(widths, style.top, style.edge != "") + "\n"
+	}
+	for i := 0; i < max; i++ {
+		if i > 0 && i%3 == 0 {
+			out += borderLine(widths, style.middle, style.edge != "") + "\n"
+		}
+		line := style.edge
+		for j := 0; j < max; j++ {
+			if j > 0 && j%3 == 0 {
+				line += "|"
+			}
+			line += " " + cells[i][j] + strings.Repeat(" ", widths[j]-len(cells[i][j])+1)
+		}
+		out += strings.TrimRight(line+style.edge, " ") + "\n"
+	}
+	if style.bottom[0] != "" {
+		out += borderLine(widths, style.bottom, style.edge != "") + "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// borderLine returns a horizontal border, with corners[0] and corners[2] at the edges, and corners[1] between the boxes.
+// The edge corners are written only if the grid has an outer border.
+func borderLine(widths [max]int, corners [3]string, edges bool) string {
+	line := ""
+	if edges {
+		line += corners[0]
+	}
+	for box := 0; box < 3; box++ {
+		if box > 0 {
+			line += corners[1]
+		}
+		length := 0
+		for j := box * 3; j < box*3+3; j++ {
+			length += widths[j] + 2
+		}
+		line += strings.Repeat("-", length)
+	}
+	if edges {
+		line += corners[2]
+	}
+	return line
+}
+
+// candidateToken returns the token for the cell at {i, j}: the given, the placed value prefixed by '+', or the candidates.
+// An empty cell with fewer than 2 candidates is written as '.', as a single digit is read back as a given.
+func candidateToken(board *datatypes.Board, i int, j int) string {
+	if board.Givens[i][j] > 0 {
+		return fmt.Sprint(board.Givens[i][j])
+	}
+	if board.Placed[i][j] > 0 {
+		return fmt.Sprint("+", board.Placed[i][j])
+	}
+	candidates := board.Candidates[i][j]
+	if candidates == nil {
+		candidates = board.PeerCandidates(datatypes.Position{X: i, Y: j})
+	}
+	values := datatypes.SortedValues(candidates)
+	if len(values) < 2 {
+		return "."
+	}
+	token := ""
+	for _, val := range values {
+		token += fmt.Sprint(val)
+	}
+	return token
+}
@@ -0,0 +1,153 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+// Package formats reads and writes sudoku puzzles in the file formats used by other sudoku programs.
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const max int = 9
+
+// Format reads and writes a Board in one file format.
+// Extensions lists the file extensions of the format, including the leading dot.
+type Format struct {
+	Name       string
+	Extensions []string
+	Read       func(r io.Reader) (*datatypes.Board, error)
+	Write      func(w io.Writer, board *datatypes.Board) error
+}
+
+var registry = []Format{
+	{Name: "grid", Extensions: []string{".txt"}, Read: ReadGrid, Write: WriteGrid},
+	{Name: "line", Read: ReadLine, Write: WriteLine},
+	{Name: "sdk", Extensions: []string{".sdk"}, Read: ReadSDK, Write: WriteSDK},
+	{Name: "sdx", Extensions: []string{".sdx"}, Read: ReadSDX, Write: WriteSDX},
+	{Name: "ss", Extensions: []string{".ss"}, Read: ReadSS, Write: WriteSS},
+	{Name: "hodoku", Read: ReadHoDoKu, Write: WriteHoDoKu},
+	{Name: "explainer", Read: ReadExplainer, Write: WriteExplainer},
+	{Name: "fpuzzles", Read: ReadFPuzzles, Write: WriteFPuzzles},
+	{Name: "sudokupad", Read: ReadSudokuPad, Write: WriteSudokuPad},
+}
+
+// Lookup returns the format with the given name.
+func Lookup(name string) (Format, bool) {
+	for _, format := range registry {
+		if format.Name == name {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+// ForFile returns the format for the extension of the file path.
+func ForFile(path string) (Format, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, format := range registry {
+		for _, e := range format.Extensions {
+			if e == ext {
+				return format, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// Names returns the names of all the formats.
+func Names() []string {
+	var names []string
+	for _, format := range registry {
+		names = append(names, format.Name)
+	}
+	return names
+}
+
+// ParseError describes a problem in the input. Line and Column start at 1, and Column is 0 if it applies to the whole line.
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// readLines returns all the lines of the input, without the line endings.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	return lines, scanner.Err()
+}
+
+// parseDigit returns the value of a cell character: 1-9 for a digit, and 0 for '.' or '0'.
+// Returns false if the character is not a cell character.
+func parseDigit(c byte) (int, bool) {
+	if c >= '1' && c <= '9' {
+		return int(c - '0'), true
+	}
+	if c == '.' || c == '0' {
+		return 0, true
+	}
+	return 0, false
+}
+
+// parseDigitRow parses a row of 9 cell characters, ignoring the characters in skip.
+func parseDigitRow(line string, lineNum int, skip string) ([max]int, error) {
+	var row [max]int
+	count := 0
+	for i := 0; i < len(line); i++ {
+		if strings.IndexByte(skip, line[i]) >= 0 {
+			continue
+		}
+		val, ok := parseDigit(line[i])
+		if !ok {
+			return row, &ParseError{lineNum, i + 1, fmt.Sprintf("unexpected character %q", line[i])}
+		}
+		if count == max {
+			return row, &ParseError{lineNum, i + 1, "more than 9 cells in the row"}
+		}
+		row[count] = val
+		count++
+	}
+	if count < max {
+		return row, &ParseError{lineNum, 0, fmt.Sprintf("expected 9 cells, got %d", count)}
+	}
+	return row, nil
+}
+
+// digitRow formats a row of values, with '.' for an empty cell.
+func digitRow(row [max]int) string {
+	s := ""
+	for _, val := range row {
+		if val == 0 {
+			s += "."
+		} else {
+			s += fmt.Sprint(val)
+		}
+	}
+	return s
+}
+
+// boardValues returns the given and placed values of the board.
+func boardValues(board *datatypes.Board) (values [max][max]int) {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			values[i][j] = board.Value(datatypes.Position{X: i, Y: j})
+		}
+	}
+	return
+}
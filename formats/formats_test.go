@@ -0,0 +1,172 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const sdkInput = `[Puzzle]
+#AJayant
+#LHard
+....45...
+8.....2.7
+..2.....4
+..6...3.2
+...1.....
+2.74..6..
+64..98...
+79...4...
+.......3.
+[State]
+1...45...
+8.....2.7
+..2.....4
+..6...3.2
+...1.....
+2.74..6..
+64..98...
+79...4...
+.......3.
+`
+
+// TestReadSDK verifies that headers, givens and placed values are read from the .sdk format.
+func TestReadSDK(t *testing.T) {
+	board, err := ReadSDK(strings.NewReader(sdkInput))
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if board.Info.Author != "Jayant" || board.Info.Level != "Hard" {
+		t.Error("Expected Jayant, Hard; got ", board.Info.Author, board.Info.Level)
+	}
+	if board.Givens[0][4] != 4 || board.Givens[0][0] != 0 || board.Placed[0][0] != 1 || board.Placed[0][4] != 0 {
+		t.Error("Expected given 4 at {0,4} and placed 1 at {0,0}; got ", board.Givens[0], board.Placed[0])
+	}
+	var out bytes.Buffer
+	if err := WriteSDK(&out, board); err != nil || out.String() != sdkInput {
+		t.Error("Expected the input to be written back, got ", out.String(), err)
+	}
+}
+
+// TestSDXRoundTrip verifies that givens, placed values and candidates, even a single one, survive writing and reading the .sdx format.
+func TestSDXRoundTrip(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 5
+	board.Placed[0][1] = 3
+	board.Candidates[0][2] = map[int]bool{1: true, 7: true}
+	board.Candidates[0][4] = map[int]bool{8: true}
+	var out bytes.Buffer
+	if err := WriteSDX(&out, board); err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if !strings.HasPrefix(out.String(), "5 u3 17 1246789 88 ") {
+		t.Error("Expected row to start with 5 u3 17 1246789 88, got ", out.String())
+	}
+	read, err := ReadSDX(&out)
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if read.Givens[0][0] != 5 || read.Placed[0][1] != 3 || len(read.Candidates[0][2]) != 2 || !read.Candidates[0][2][7] {
+		t.Error("Expected 5, 3 and {1,7}; got ", read.Givens[0][0], read.Placed[0][1], read.Candidates[0][2])
+	}
+	if read.Givens[0][4] != 0 || len(read.Candidates[0][4]) != 1 || !read.Candidates[0][4][8] {
+		t.Error("Expected the single candidate 8, got ", read.Givens[0][4], read.Candidates[0][4])
+	}
+}
+
+// TestReadSSErrors verifies the position reported for invalid .ss input.
+func TestReadSSErrors(t *testing.T) {
+	_, err := ReadSS(strings.NewReader("*-----------*\n|..a|...|...|\n"))
+	if err == nil || err.Error() != "line 2, column 4: unexpected character 'a'" {
+		t.Error("Expected error at line 2, column 4; got ", err)
+	}
+}
+
+// TestHoDoKuRoundTrip verifies that a HoDoKu candidate grid is written with '+' for placed values, and read back.
+func TestHoDoKuRoundTrip(t *testing.T) {
+	board, err := ReadHoDoKu(strings.NewReader(strings.Replace(strings.Repeat(".", 81), "..", "4+2", 1)))
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	board.Candidates[8][8] = map[int]bool{1: true, 3: true}
+	var out bytes.Buffer
+	if err := WriteHoDoKu(&out, board); err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[0], ".---") || !strings.HasPrefix(lines[1], "| 4 ") || !strings.Contains(lines[1], " +2 ") {
+		t.Error("Expected a bordered grid with 4 and +2, got\n", out.String())
+	}
+	read, err := ReadExplainer(&out)
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if read.Givens[0][0] != 4 || read.Placed[0][1] != 2 || len(read.Candidates[0][2]) != 7 || !read.Candidates[8][8][3] {
+		t.Error("Expected 4, +2 and candidates; got ", read.Givens[0][0], read.Placed[0][1], read.Candidates[0][2], read.Candidates[8][8])
+	}
+}
+
+// TestSingleCandidateRoundTrip verifies that an empty cell with a single candidate is not read back as a given.
+func TestSingleCandidateRoundTrip(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Candidates[4][4] = map[int]bool{7: true}
+	var out bytes.Buffer
+	if err := WriteExplainer(&out, board); err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	read, err := ReadHoDoKu(&out)
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if read.Givens[4][4] != 0 || read.Placed[4][4] != 0 {
+		t.Error("Expected an empty cell, got ", read.Givens[4][4], read.Placed[4][4])
+	}
+}
+
+// TestLZString verifies that lz-string data is compressed as f-puzzles expects, and decompressed back.
+func TestLZString(t *testing.T) {
+	input := `{"size":9,"title":"Sudoku – ok","grid":[]}`
+	compressed := lzCompressToBase64(input)
+	if !strings.HasPrefix(compressed, "N4Ig") {
+		t.Error("Expected prefix N4Ig, got ", compressed)
+	}
+	output, err := lzDecompressFromBase64(compressed)
+	if err != nil || output != input {
+		t.Error("Expected ", input, ", got ", output, err)
+	}
+}
+
+// TestFPuzzlesRoundTrip verifies that givens and placed values survive an f-puzzles link, and that unsupported constraints
+// and invalid pencil marks are reported.
+func TestFPuzzlesRoundTrip(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 5
+	board.Placed[8][8] = 3
+	board.Info.Title = "Classic"
+	var out bytes.Buffer
+	if err := WriteSudokuPad(&out, board); err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	read, err := ReadSudokuPad(&out)
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if read.Givens[0][0] != 5 || read.Placed[8][8] != 3 || read.Info.Title != "Classic" {
+		t.Error("Expected 5, 3 and Classic; got ", read.Givens[0][0], read.Placed[8][8], read.Info.Title)
+	}
+	data := lzCompressToBase64(`{"size":9,"grid":[[{}` + strings.Repeat(`,{}`, 8) + `]` + strings.Repeat(`,[{}`+strings.Repeat(`,{}`, 8)+`]`, 8) + `],"antiknight":true,"killercage":[{"cells":["R1C1"]}],"arrow":[]}`)
+	_, err = ReadFPuzzles(strings.NewReader(fpuzzlesURL + data))
+	if err == nil || err.Error() != "unsupported constraints: antiknight, killercage" {
+		t.Error("Expected antiknight and killercage to be unsupported, got ", err)
+	}
+	data = lzCompressToBase64(`{"size":9,"grid":[[{},{"centerPencilMarks":[2,10]}` + strings.Repeat(`,{}`, 7) + `]` + strings.Repeat(`,[{}`+strings.Repeat(`,{}`, 8)+`]`, 8) + `]}`)
+	_, err = ReadFPuzzles(strings.NewReader(fpuzzlesURL + data))
+	if err == nil || err.Error() != "f-puzzles: invalid pencil mark 10 in row 1, column 2" {
+		t.Error("Expected an invalid pencil mark in row 1, column 2, got ", err)
+	}
+}
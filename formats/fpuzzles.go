@@ -0,0 +1,331 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const fpuzzlesURL, sudokupadURL = "https://f-puzzles.com/?load=", "https://sudokupad.app/"
+
+// UnsupportedError lists the constraints of a puzzle which the solver does not support.
+// It is returned together with the board, which contains the puzzle without those constraints.
+type UnsupportedError struct {
+	Constraints []string
+}
+
+func (e *UnsupportedError) Error() string {
+	return "unsupported constraints: " + strings.Join(e.Constraints, ", ")
+}
+
+// fpuzzlesCell is a cell of the grid of an f-puzzles document.
+type fpuzzlesCell struct {
+	Value             int   `json:"value,omitempty"`
+	Given             bool  `json:"given,omitempty"`
+	CenterPencilMarks []int `json:"centerPencilMarks,omitempty"`
+	Region            *int  `json:"region,omitempty"`
+}
+
+// fpuzzlesKnownKeys are the keys of an f-puzzles document which are read into the board, or which have no effect on the solution.
+var fpuzzlesKnownKeys = map[string]bool{"size": true, "grid": true, "title": true, "author": true, "ruleset": true, "solution": true}
+
+// ReadFPuzzles reads an f-puzzles share string. The input can be the link, or only the compressed data after "load=".
+// Returns an *UnsupportedError with the board, if the puzzle has constraints other than the standard sudoku rules.
+func ReadFPuzzles(r io.Reader) (*datatypes.Board, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data := strings.TrimSpace(string(input))
+	if index := strings.Index(data, "load="); index >= 0 {
+		data = data[index+len("load="):]
+	}
+	return decodeFPuzzles(data)
+}
+
+// WriteFPuzzles writes the board as an f-puzzles link.
+func WriteFPuzzles(w io.Writer, board *datatypes.Board) error {
+	data, err := encodeFPuzzles(board)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, fpuzzlesURL+data+"\n")
+	return err
+}
+
+// ReadSudokuPad reads a SudokuPad share string. The input can be the link, or only the puzzle id after the host.
+// Puzzles converted from f-puzzles, with an id starting with "fpuzzles", and plain SudokuPad puzzles starting with "scl" are supported.
+// Short ids which need to be looked up on the SudokuPad server are not supported.
+// Returns an *UnsupportedError with the board, if the puzzle has constraints other than the standard sudoku rules.
+func ReadSudokuPad(r io.Reader) (*datatypes.Board, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(string(input))
+	if index := strings.Index(id, "://"); index >= 0 {
+		id = id[index+len("://"):]
+		if index = strings.Index(id, "/"); index >= 0 {
+			id = strings.TrimPrefix(id[index+1:], "sudoku/")
+		}
+	}
+	if index := strings.IndexAny(id, "?#"); index >= 0 {
+		id = id[:index]
+	}
+	switch {
+	case strings.HasPrefix(id, "fpuzzles"):
+		return decodeFPuzzles(strings.TrimPrefix(id, "fpuzzles"))
+	case strings.HasPrefix(id, "scl"):
+		return decodeSCL(strings.TrimPrefix(id, "scl"))
+	}
+	return nil, fmt.Errorf("sudokupad: puzzle id %q can only be loaded from the SudokuPad server", id)
+}
+
+// WriteSudokuPad writes the board as a SudokuPad link, with the puzzle as f-puzzles data.
+func WriteSudokuPad(w io.Writer, board *datatypes.Board) error {
+	data, err := encodeFPuzzles(board)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, sudokupadURL+"fpuzzles"+data+"\n")
+	return err
+}
+
+// decompressJSON decompresses the lz-string data, and unmarshals the JSON into the keys of the document.
+func decompressJSON(data string) (map[string]json.RawMessage, error) {
+	if unescaped, err := url.QueryUnescape(data); err == nil {
+		data = unescaped
+	}
+	text, err := lzDecompressFromBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	document := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		return nil, fmt.Errorf("puzzle data is not valid JSON: %v", err)
+	}
+	return document, nil
+}
+
+// decodeFPuzzles decodes the compressed f-puzzles document into a board.
+func decodeFPuzzles(data string) (*datatypes.Board, error) {
+	document, err := decompressJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("f-puzzles: %v", err)
+	}
+	var size int
+	var grid [][]fpuzzlesCell
+	if err := json.Unmarshal(document["size"], &size); err != nil || size != max {
+		return nil, fmt.Errorf("f-puzzles: grid size %s is not supported, only 9x9 grids are", document["size"])
+	}
+	if err := json.Unmarshal(document["grid"], &grid); err != nil || len(grid) != max {
+		return nil, fmt.Errorf("f-puzzles: invalid grid")
+	}
+	board := datatypes.NewBoard()
+	json.Unmarshal(document["title"], &board.Info.Title)
+	json.Unmarshal(document["author"], &board.Info.Author)
+	json.Unmarshal(document["ruleset"], &board.Info.Description)
+	var regions [max][max]int
+	for i, row := range grid {
+		if len(row) != max {
+			return nil, fmt.Errorf("f-puzzles: row %d of the grid has %d cells", i+1, len(row))
+		}
+		for j, cell := range row {
+			if cell.Value < 0 || cell.Value > max {
+				return nil, fmt.Errorf("f-puzzles: invalid value %d in row %d, column %d", cell.Value, i+1, j+1)
+			}
+			if cell.Given {
+				board.Givens[i][j] = cell.Value
+			} else {
+				board.Placed[i][j] = cell.Value
+			}
+			if len(cell.CenterPencilMarks) > 0 {
+				board.Candidates[i][j] = make(map[int]bool)
+				for _, val := range cell.CenterPencilMarks {
+					if val < 1 || val > max {
+						return nil, fmt.Errorf("f-puzzles: invalid pencil mark %d in row %d, column %d", val, i+1, j+1)
+					}
+					board.Candidates[i][j][val] = true
+				}
+			}
+			regions[i][j] = (i/3)*3 + j/3
+			if cell.Region != nil {
+				regions[i][j] = *cell.Region
+			}
+		}
+	}
+	var unsupported []string
+	if !standardRegions(regions) {
+		unsupported = append(unsupported, "irregular regions")
+	}
+	for key, value := range document {
+		if !fpuzzlesKnownKeys[key] && isConstraint(value) {
+			unsupported = append(unsupported, key)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return board, &UnsupportedError{unsupported}
+	}
+	return board, nil
+}
+
+// encodeFPuzzles encodes the board as a compressed f-puzzles document.
+func encodeFPuzzles(board *datatypes.Board) (string, error) {
+	grid := make([][]fpuzzlesCell, max)
+	for i := 0; i < max; i++ {
+		grid[i] = make([]fpuzzlesCell, max)
+		for j := 0; j < max; j++ {
+			cell := &grid[i][j]
+			cell.Value = board.Value(datatypes.Position{X: i, Y: j})
+			cell.Given = board.Givens[i][j] > 0
+			if cell.Value == 0 {
+				cell.CenterPencilMarks = datatypes.SortedValues(board.Candidates[i][j])
+			}
+		}
+	}
+	document := struct {
+		Size    int              `json:"size"`
+		Title   string           `json:"title,omitempty"`
+		Author  string           `json:"author,omitempty"`
+		Ruleset string           `json:"ruleset,omitempty"`
+		Grid    [][]fpuzzlesCell `json:"grid"`
+	}{max, board.Info.Title, board.Info.Author, board.Info.Description, grid}
+	text, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	return lzCompressToBase64(string(text)), nil
+}
+
+// isConstraint returns true if the value of a key of the document sets a constraint, i.e. it is true or a non-empty list.
+func isConstraint(value json.RawMessage) bool {
+	text := strings.TrimSpace(string(value))
+	return text != "false" && text != "null" && text != "[]" && text != "{}" && text != `""`
+}
+
+// standardRegions returns true if the regions are the 3x3 blocks of the grid, whatever their numbering.
+func standardRegions(regions [max][max]int) bool {
+	blockOf := make(map[int]int)
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			block := (i/3)*3 + j/3
+			if b, ok := blockOf[regions[i][j]]; ok && b != block {
+				return false
+			}
+			blockOf[regions[i][j]] = block
+		}
+	}
+	return len(blockOf) == max
+}
+
+// sclCell is a cell of a SudokuPad puzzle. The value of a given is a digit, or a string with the digit.
+type sclCell struct {
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// sclCage is a cage of a SudokuPad puzzle. Cages without cells hold the metadata, as "title: ..." values.
+type sclCage struct {
+	Cells [][]int `json:"cells"`
+	Value string  `json:"value"`
+}
+
+// sclKnownKeys are the keys of a SudokuPad puzzle which are read into the board, or which have no effect on the solution.
+var sclKnownKeys = map[string]bool{"id": true, "cellSize": true, "cells": true, "regions": true, "cages": true, "settings": true}
+
+// decodeSCL decodes a SudokuPad puzzle into a board.
+func decodeSCL(data string) (*datatypes.Board, error) {
+	document, err := decompressJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("sudokupad: %v (compacted puzzle data is not supported; share the puzzle as f-puzzles data instead)", err)
+	}
+	var cells [][]sclCell
+	if err := json.Unmarshal(document["cells"], &cells); err != nil || len(cells) != max {
+		return nil, fmt.Errorf("sudokupad: grid size %d is not supported, only 9x9 grids are", len(cells))
+	}
+	board := datatypes.NewBoard()
+	for i, row := range cells {
+		if len(row) != max {
+			return nil, fmt.Errorf("sudokupad: grid size %dx%d is not supported, only 9x9 grids are", max, len(row))
+		}
+		for j, cell := range row {
+			text := strings.Trim(string(cell.Value), `"`)
+			if text == "" || text == "null" {
+				continue
+			}
+			if len(text) != 1 || text[0] < '1' || text[0] > '9' {
+				return nil, fmt.Errorf("sudokupad: invalid value %s in row %d, column %d", cell.Value, i+1, j+1)
+			}
+			board.Givens[i][j] = int(text[0] - '0')
+		}
+	}
+	var unsupported []string
+	var regions [][][]int
+	json.Unmarshal(document["regions"], &regions)
+	if len(regions) > 0 && !standardRegions(sclRegions(regions)) {
+		unsupported = append(unsupported, "irregular regions")
+	}
+	var cages []sclCage
+	json.Unmarshal(document["cages"], &cages)
+	for _, cage := range cages {
+		if len(cage.Cells) > 0 {
+			unsupported = append(unsupported, "cages")
+			break
+		}
+		setSCLMetadata(&board.Info, cage.Value)
+	}
+	for key, value := range document {
+		if !sclKnownKeys[key] && isConstraint(value) {
+			unsupported = append(unsupported, key)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return board, &UnsupportedError{unsupported}
+	}
+	return board, nil
+}
+
+// sclRegions returns the region number of each cell, from the lists of [row, column] cells of each region.
+// Cells which are in no region get a region number of their own.
+func sclRegions(regions [][][]int) (numbers [max][max]int) {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			numbers[i][j] = -1 - (i*max + j)
+		}
+	}
+	for number, region := range regions {
+		for _, cell := range region {
+			if len(cell) == 2 && cell[0] >= 0 && cell[0] < max && cell[1] >= 0 && cell[1] < max {
+				numbers[cell[0]][cell[1]] = number
+			}
+		}
+	}
+	return
+}
+
+// setSCLMetadata sets the field of info for a metadata value like "title: Puzzle".
+func setSCLMetadata(info *datatypes.Info, value string) {
+	index := strings.Index(value, ":")
+	if index < 0 {
+		return
+	}
+	text := strings.TrimSpace(value[index+1:])
+	switch strings.TrimSpace(value[:index]) {
+	case "title":
+		info.Title = text
+	case "author":
+		info.Author = text
+	case "rules":
+		info.Description = text
+	}
+}
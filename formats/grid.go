@@ -0,0 +1,121 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// ReadGrid reads a puzzle in the input format of the solver: 9 lines of 9 elements separated by spaces,
+// where an element is a value from 1 to 9, or _ for an empty cell.
+func ReadGrid(r io.Reader) (*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	board := datatypes.NewBoard()
+	rownum := 0
+	for index, line := range lines {
+		lineNum := index + 1
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if rownum == max {
+			return nil, &ParseError{lineNum, 0, "more than 9 rows"}
+		}
+		cells := tokenCells(line, lineNum)
+		if len(cells) != max {
+			return nil, &ParseError{lineNum, 0, fmt.Sprintf("expected 9 elements, got %d", len(cells))}
+		}
+		for j, cell := range cells {
+			if cell.token == "_" {
+				continue
+			}
+			if len(cell.token) != 1 || cell.token[0] < '1' || cell.token[0] > '9' {
+				return nil, &ParseError{lineNum, cell.column, fmt.Sprintf("element %q should be from 1 to 9, or _", cell.token)}
+			}
+			board.Givens[rownum][j] = int(cell.token[0] - '0')
+		}
+		rownum++
+	}
+	if rownum != max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 9 rows, got %d", rownum)}
+	}
+	return board, nil
+}
+
+// WriteGrid writes the given and placed values of the board in the input format of the solver.
+func WriteGrid(w io.Writer, board *datatypes.Board) error {
+	values := boardValues(board)
+	out := ""
+	for i := 0; i < max; i++ {
+		var row []string
+		for j := 0; j < max; j++ {
+			if values[i][j] == 0 {
+				row = append(row, "_")
+			} else {
+				row = append(row, fmt.Sprint(values[i][j]))
+			}
+		}
+		out += strings.Join(row, " ") + "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// ReadLine reads a puzzle given as a single line of 81 characters, with '.' or '0' for an empty cell.
+func ReadLine(r io.Reader) (*datatypes.Board, error) {
+	boards, err := ReadLineList(r)
+	if err != nil {
+		return nil, err
+	}
+	return boards[0], nil
+}
+
+// ReadLineList reads a list of puzzles, one puzzle on each line, as in ReadLine. Empty lines, and lines starting with '#', are skipped.
+func ReadLineList(r io.Reader) ([]*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var boards []*datatypes.Board
+	for index, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(line) != max*max {
+			return nil, &ParseError{index + 1, 0, fmt.Sprintf("expected 81 cells, got %d", len(line))}
+		}
+		board := datatypes.NewBoard()
+		for i := 0; i < max; i++ {
+			row, err := parseDigitRow(line[i*max:(i+1)*max], index+1, "")
+			if err != nil {
+				err.(*ParseError).Column += i * max
+				return nil, err
+			}
+			board.Givens[i] = row
+		}
+		boards = append(boards, board)
+	}
+	if len(boards) == 0 {
+		return nil, &ParseError{len(lines), 0, "no puzzle found"}
+	}
+	return boards, nil
+}
+
+// WriteLine writes the given and placed values of the board as a single line of 81 characters, with '.' for an empty cell.
+func WriteLine(w io.Writer, board *datatypes.Board) error {
+	values := boardValues(board)
+	out := ""
+	for i := 0; i < max; i++ {
+		out += digitRow(values[i])
+	}
+	_, err := io.WriteString(w, out+"\n")
+	return err
+}
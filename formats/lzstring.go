@@ -0,0 +1,227 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// lzBase64 is the alphabet of the base64 output of lz-string.
+const lzBase64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+var errLZString = errors.New("invalid lz-string data")
+
+// lzKey returns a map key for a sequence of UTF-16 code units.
+func lzKey(units []uint16) string {
+	var key strings.Builder
+	for _, u := range units {
+		key.WriteByte(byte(u >> 8))
+		key.WriteByte(byte(u))
+	}
+	return key.String()
+}
+
+// lzWriter collects the bits of the compressed data, bitsPerChar at a time.
+type lzWriter struct {
+	bitsPerChar int
+	out         []byte
+	val         int
+	position    int
+}
+
+// writeBits writes the lowest n bits of value, lowest bit first.
+func (w *lzWriter) writeBits(n int, value int) {
+	for i := 0; i < n; i++ {
+		w.val = (w.val << 1) | (value & 1)
+		if w.position == w.bitsPerChar-1 {
+			w.position = 0
+			w.out = append(w.out, lzBase64[w.val])
+			w.val = 0
+		} else {
+			w.position++
+		}
+		value >>= 1
+	}
+}
+
+// flush writes the last, partially filled character.
+func (w *lzWriter) flush() {
+	for {
+		w.val <<= 1
+		if w.position == w.bitsPerChar-1 {
+			w.out = append(w.out, lzBase64[w.val])
+			return
+		}
+		w.position++
+	}
+}
+
+// lzCompressToBase64 compresses the string as the compressToBase64 function of lz-string does.
+func lzCompressToBase64(input string) string {
+	units := utf16.Encode([]rune(input))
+	dictionary := make(map[string]int)
+	toCreate := make(map[string]bool)
+	w := &lzWriter{bitsPerChar: 6}
+	enlargeIn, dictSize, numBits := 2, 3, 2
+	var current []uint16
+	enlarge := func() {
+		enlargeIn--
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+	// writeCurrent writes the code for the current sequence, creating the entry for a new character first.
+	writeCurrent := func() {
+		key := lzKey(current)
+		if toCreate[key] {
+			if current[0] < 256 {
+				w.writeBits(numBits, 0)
+				w.writeBits(8, int(current[0]))
+			} else {
+				w.writeBits(numBits, 1)
+				w.writeBits(16, int(current[0]))
+			}
+			enlarge()
+			delete(toCreate, key)
+		} else {
+			w.writeBits(numBits, dictionary[key])
+		}
+		enlarge()
+	}
+	for _, c := range units {
+		charKey := lzKey([]uint16{c})
+		if _, ok := dictionary[charKey]; !ok {
+			dictionary[charKey] = dictSize
+			dictSize++
+			toCreate[charKey] = true
+		}
+		next := append(append([]uint16{}, current...), c)
+		if _, ok := dictionary[lzKey(next)]; ok {
+			current = next
+			continue
+		}
+		writeCurrent()
+		dictionary[lzKey(next)] = dictSize
+		dictSize++
+		current = []uint16{c}
+	}
+	if len(current) > 0 {
+		writeCurrent()
+	}
+	w.writeBits(numBits, 2)
+	w.flush()
+	out := string(w.out)
+	if padding := len(out) % 4; padding > 0 {
+		out += strings.Repeat("=", 4-padding)
+	}
+	return out
+}
+
+// lzReader reads the bits of the compressed data.
+type lzReader struct {
+	input    string
+	val      int
+	position int
+	index    int
+}
+
+// value returns the 6 bit value of the base64 character at index.
+// The URI safe alphabet of lz-string, and a '+' turned into a space by URL decoding, are accepted as well.
+func (r *lzReader) value(index int) int {
+	if index >= len(r.input) {
+		return 0
+	}
+	switch c := r.input[index]; c {
+	case ' ':
+		return 62
+	case '-':
+		return 63
+	default:
+		if i := strings.IndexByte(lzBase64, c); i >= 0 && i < 64 {
+			return i
+		}
+	}
+	return 0
+}
+
+// readBits reads n bits, lowest bit first.
+func (r *lzReader) readBits(n int) int {
+	bits := 0
+	for power := 0; power < n; power++ {
+		if r.val&r.position > 0 {
+			bits |= 1 << power
+		}
+		r.position >>= 1
+		if r.position == 0 {
+			r.position = 32
+			r.val = r.value(r.index)
+			r.index++
+		}
+	}
+	return bits
+}
+
+// lzDecompressFromBase64 decompresses the string as the decompressFromBase64 function of lz-string does.
+func lzDecompressFromBase64(input string) (string, error) {
+	input = strings.TrimRight(strings.TrimSpace(input), "=")
+	if input == "" {
+		return "", errLZString
+	}
+	r := &lzReader{input: input, position: 32, index: 1}
+	r.val = r.value(0)
+	dictionary := [][]uint16{{0}, {1}, {2}}
+	enlargeIn, numBits := 4, 3
+	var entry []uint16
+	switch r.readBits(2) {
+	case 0:
+		entry = []uint16{uint16(r.readBits(8))}
+	case 1:
+		entry = []uint16{uint16(r.readBits(16))}
+	default:
+		return "", nil
+	}
+	dictionary = append(dictionary, entry)
+	w := entry
+	result := append([]uint16{}, entry...)
+	for {
+		if r.index > len(input) {
+			return "", errLZString
+		}
+		c := r.readBits(numBits)
+		switch c {
+		case 0, 1:
+			size := 8
+			if c == 1 {
+				size = 16
+			}
+			dictionary = append(dictionary, []uint16{uint16(r.readBits(size))})
+			c = len(dictionary) - 1
+			enlargeIn--
+		case 2:
+			return string(utf16.Decode(result)), nil
+		}
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+		if c < len(dictionary) {
+			entry = dictionary[c]
+		} else if c == len(dictionary) {
+			entry = append(append([]uint16{}, w...), w[0])
+		} else {
+			return "", errLZString
+		}
+		result = append(result, entry...)
+		dictionary = append(dictionary, append(append([]uint16{}, w...), entry[0]))
+		enlargeIn--
+		w = entry
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+}
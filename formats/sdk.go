@@ -0,0 +1,145 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// ReadSDK reads a puzzle in the SadMan Software .sdk format.
+// Lines starting with '#' are headers, where the letter after '#' gives the meaning of the rest of the line.
+// The grid is given as 9 lines of 9 characters, with '.' for an empty cell.
+// An optional [State] section gives the grid as it was left by the player; values in it which are not givens are placed values.
+func ReadSDK(r io.Reader) (*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	board := datatypes.NewBoard()
+	section := "[Puzzle]"
+	rows := map[string]int{}
+	for index, line := range lines {
+		lineNum := index + 1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "#"):
+			setSDKHeader(&board.Info, trimmed)
+		case strings.HasPrefix(trimmed, "["):
+			section = trimmed
+			if section != "[Puzzle]" && section != "[State]" {
+				return nil, &ParseError{lineNum, 1, "unknown section " + section}
+			}
+		default:
+			rownum := rows[section]
+			if rownum == max {
+				return nil, &ParseError{lineNum, 0, "more than 9 rows in section " + section}
+			}
+			row, err := parseDigitRow(trimmed, lineNum, " ")
+			if err != nil {
+				return nil, err
+			}
+			if section == "[Puzzle]" {
+				board.Givens[rownum] = row
+			} else if err := setPlaced(board, rownum, row, lineNum); err != nil {
+				return nil, err
+			}
+			rows[section]++
+		}
+	}
+	if rows["[Puzzle]"] != max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 9 rows, got %d", rows["[Puzzle]"])}
+	}
+	if rows["[State]"] != 0 && rows["[State]"] != max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 9 rows in section [State], got %d", rows["[State]"])}
+	}
+	return board, nil
+}
+
+// WriteSDK writes the board in the SadMan Software .sdk format.
+// The [State] section is written only if the board has placed values.
+func WriteSDK(w io.Writer, board *datatypes.Board) error {
+	out := "[Puzzle]\n" + sdkHeaders(board.Info)
+	for i := 0; i < max; i++ {
+		out += digitRow(board.Givens[i]) + "\n"
+	}
+	if hasPlaced(board) {
+		values := boardValues(board)
+		out += "[State]\n"
+		for i := 0; i < max; i++ {
+			out += digitRow(values[i]) + "\n"
+		}
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// sdkHeaderFields maps the letter of a SadMan header to the corresponding field of Info.
+var sdkHeaderFields = []struct {
+	letter byte
+	field  func(info *datatypes.Info) *string
+}{
+	{'A', func(info *datatypes.Info) *string { return &info.Author }},
+	{'D', func(info *datatypes.Info) *string { return &info.Description }},
+	{'C', func(info *datatypes.Info) *string { return &info.Comment }},
+	{'B', func(info *datatypes.Info) *string { return &info.Date }},
+	{'S', func(info *datatypes.Info) *string { return &info.Source }},
+	{'L', func(info *datatypes.Info) *string { return &info.Level }},
+	{'U', func(info *datatypes.Info) *string { return &info.URL }},
+}
+
+// setSDKHeader sets the field of info for a header line. Unknown headers are ignored.
+func setSDKHeader(info *datatypes.Info, line string) {
+	if len(line) < 2 {
+		return
+	}
+	for _, header := range sdkHeaderFields {
+		if line[1] == header.letter {
+			*header.field(info) = strings.TrimSpace(line[2:])
+		}
+	}
+}
+
+// sdkHeaders returns the header lines for the non-empty fields of info.
+func sdkHeaders(info datatypes.Info) string {
+	out := ""
+	for _, header := range sdkHeaderFields {
+		if value := *header.field(&info); value != "" {
+			out += "#" + string(header.letter) + value + "\n"
+		}
+	}
+	return out
+}
+
+// setPlaced sets the values of a row of the player's grid which are not givens as placed values.
+// Returns an error if the row changes a given value.
+func setPlaced(board *datatypes.Board, rownum int, row [max]int, lineNum int) error {
+	for j, val := range row {
+		given := board.Givens[rownum][j]
+		if given > 0 {
+			if val != given {
+				return &ParseError{lineNum, j + 1, fmt.Sprintf("given %d is changed to %d", given, val)}
+			}
+			continue
+		}
+		board.Placed[rownum][j] = val
+	}
+	return nil
+}
+
+// hasPlaced returns true if there is a placed value on the board.
+func hasPlaced(board *datatypes.Board) bool {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if board.Placed[i][j] > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// ReadSDX reads a puzzle in the SadMan Software .sdx format.
+// Header lines are the same as in the .sdk format. Each row of the grid is a line with 9 cells separated by spaces.
+// A cell is a single digit for a given, 'u' followed by a digit for a value placed by the player,
+// or the list of candidates of an empty cell, e.g. 1379.
+func ReadSDX(r io.Reader) (*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	board := datatypes.NewBoard()
+	rownum := 0
+	for index, line := range lines {
+		lineNum := index + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			setSDKHeader(&board.Info, trimmed)
+			continue
+		}
+		if rownum == max {
+			return nil, &ParseError{lineNum, 0, "more than 9 rows"}
+		}
+		if err := readSDXRow(board, rownum, line, lineNum); err != nil {
+			return nil, err
+		}
+		rownum++
+	}
+	if rownum != max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 9 rows, got %d", rownum)}
+	}
+	return board, nil
+}
+
+// readSDXRow sets the cells of a row from the space separated tokens of the line.
+func readSDXRow(board *datatypes.Board, rownum int, line string, lineNum int) error {
+	column := 0
+	for start := 0; start < len(line); {
+		if line[start] == ' ' || line[start] == '\t' {
+			start++
+			continue
+		}
+		end := start
+		for end < len(line) && line[end] != ' ' && line[end] != '\t' {
+			end++
+		}
+		if column == max {
+			return &ParseError{lineNum, start + 1, "more than 9 cells in the row"}
+		}
+		if err := readSDXCell(board, datatypes.Position{X: rownum, Y: column}, line[start:end]); err != nil {
+			return &ParseError{lineNum, start + 1, err.Error()}
+		}
+		column++
+		start = end
+	}
+	if column < max {
+		return &ParseError{lineNum, 0, fmt.Sprintf("expected 9 cells, got %d", column)}
+	}
+	return nil
+}
+
+// readSDXCell sets the cell at pos from its token.
+func readSDXCell(board *datatypes.Board, pos datatypes.Position, token string) error {
+	placed := strings.HasPrefix(token, "u")
+	if placed {
+		token = token[1:]
+	}
+	candidates := make(map[int]bool)
+	for i := 0; i < len(token); i++ {
+		if token[i] < '1' || token[i] > '9' {
+			return fmt.Errorf("unexpected character %q in cell", token[i])
+		}
+		candidates[int(token[i]-'0')] = true
+	}
+	switch {
+	case len(token) == 0:
+		return fmt.Errorf("empty cell")
+	case placed && len(token) > 1:
+		return fmt.Errorf("placed value must be a single digit")
+	case placed:
+		board.Placed[pos.X][pos.Y] = int(token[0] - '0')
+	case len(token) == 1:
+		board.Givens[pos.X][pos.Y] = int(token[0] - '0')
+	default:
+		board.Candidates[pos.X][pos.Y] = candidates
+	}
+	return nil
+}
+
+// WriteSDX writes the board in the SadMan Software .sdx format.
+// An empty cell without pencil marks is written with the values not used by its peers. A single digit is read as a given,
+// so a cell with a single candidate is written with the digit twice, which is read back as the candidate.
+func WriteSDX(w io.Writer, board *datatypes.Board) error {
+	out := sdkHeaders(board.Info)
+	for i := 0; i < max; i++ {
+		var cells []string
+		for j := 0; j < max; j++ {
+			cells = append(cells, sdxCell(board, i, j))
+		}
+		out += strings.Join(cells, " ") + "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// sdxCell returns the token for the cell at {i, j}.
+func sdxCell(board *datatypes.Board, i int, j int) string {
+	if board.Givens[i][j] > 0 {
+		return fmt.Sprint(board.Givens[i][j])
+	}
+	if board.Placed[i][j] > 0 {
+		return fmt.Sprint("u", board.Placed[i][j])
+	}
+	candidates := board.Candidates[i][j]
+	if candidates == nil {
+		candidates = board.PeerCandidates(datatypes.Position{X: i, Y: j})
+	}
+	values := datatypes.SortedValues(candidates)
+	switch len(values) {
+	case 0:
+		// only a conflict leaves a cell without candidates, and the format has no empty cell.
+		return "123456789"
+	case 1:
+		return strings.Repeat(fmt.Sprint(values[0]), 2)
+	}
+	token := ""
+	for _, val := range values {
+		token += fmt.Sprint(val)
+	}
+	return token
+}
@@ -0,0 +1,69 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const ssBorder, ssSeparator = "*-----------*", "|---+---+---|"
+
+// ReadSS reads a puzzle in the Simple Sudoku .ss format.
+// The grid is given as 9 lines of 9 characters, with '.' for an empty cell.
+// Box borders made of '|', '-', '+' and '*' are optional, and are ignored.
+func ReadSS(r io.Reader) (*datatypes.Board, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	board := datatypes.NewBoard()
+	rownum := 0
+	for index, line := range lines {
+		lineNum := index + 1
+		trimmed := strings.TrimSpace(line)
+		if strings.Trim(trimmed, "|-+* ") == "" {
+			continue
+		}
+		if rownum == max {
+			return nil, &ParseError{lineNum, 0, "more than 9 rows"}
+		}
+		row, err := parseDigitRow(strings.Map(ssCellChar, line), lineNum, " |")
+		if err != nil {
+			return nil, err
+		}
+		board.Givens[rownum] = row
+		rownum++
+	}
+	if rownum != max {
+		return nil, &ParseError{len(lines), 0, fmt.Sprintf("expected 9 rows, got %d", rownum)}
+	}
+	return board, nil
+}
+
+// ssCellChar maps the other characters used for an empty cell to '.'.
+func ssCellChar(c rune) rune {
+	if c == 'x' || c == 'X' || c == '_' {
+		return '.'
+	}
+	return c
+}
+
+// WriteSS writes the given values of the board in the Simple Sudoku .ss format, with box borders.
+func WriteSS(w io.Writer, board *datatypes.Board) error {
+	out := ssBorder + "\n"
+	for i := 0; i < max; i++ {
+		if i > 0 && i%3 == 0 {
+			out += ssSeparator + "\n"
+		}
+		row := digitRow(board.Givens[i])
+		out += "|" + row[0:3] + "|" + row[3:6] + "|" + row[6:9] + "|\n"
+	}
+	out += ssBorder + "\n"
+	_, err := io.WriteString(w, out)
+	return err
+}
@@ -0,0 +1,104 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// generate writes new puzzles with a unique solution, of the difficulty level if one is given.
+func generate(args []string) {
+	flags := newFlagSet("generate", "", "Generate puzzles with a unique solution.\n"+
+		"A random solution is made, and values are removed in a random order while the solution stays unique.")
+	to := addOutputFlag(flags, "line")
+	number := flags.Int("n", 1, "number of puzzles")
+	level := flags.String("difficulty", "", "difficulty level of the puzzles: easy, medium or hard. Any level if not given")
+	minGivens := flags.Int("givens", 0, "stop removing values at this number of givens. The puzzles are minimal if not given")
+	attempts := flags.Int("attempts", 100, "number of puzzles made for each puzzle written, to find the difficulty level")
+	seed := flags.Int64("seed", 0, "seed of the random numbers, to generate the same puzzles again. Random if not given")
+	parseFlags(flags, args)
+	format := lookupOutputFormat(*to)
+	if *level != "" && *level != easy && *level != medium && *level != hard {
+		handleInvalidInput("unknown difficulty level "+*level, nil)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	var puzzles []*datatypes.Board
+	for len(puzzles) < *number {
+		board, ok := generatePuzzle(rng, *level, *minGivens, *attempts)
+		if !ok {
+			handleError(fmt.Sprintf("no %s puzzle found in %d attempts", *level, *attempts), nil)
+		}
+		puzzles = append(puzzles, board)
+	}
+	writePuzzles(format, puzzles)
+	fmt.Fprintln(os.Stderr, "Seed:", *seed)
+}
+
+// generatePuzzle makes up to attempts puzzles, until one has the difficulty level, or any level if level is empty.
+func generatePuzzle(rng *rand.Rand, level string, minGivens int, attempts int) (*datatypes.Board, bool) {
+	for attempt := 0; attempt < attempts; attempt++ {
+		board := removeValues(rng, randomSolution(rng), minGivens)
+		_, boardLevel, _ := solveBoard(board, 2)
+		if level == "" || boardLevel == level {
+			board.Info.Level = boardLevel
+			return board, true
+		}
+	}
+	return nil, false
+}
+
+// randomSolution returns a random solved grid, where all the values are givens.
+// The cells are filled row by row with the values in a random order, backtracking when a cell has no possible value.
+func randomSolution(rng *rand.Rand) *datatypes.Board {
+	board := datatypes.NewBoard()
+	fillCells(rng, board, 0)
+	return board
+}
+
+// fillCells fills the cells from the index, row by row, with the values in a random order.
+// Returns false if the cells cannot be filled.
+func fillCells(rng *rand.Rand, board *datatypes.Board, index int) bool {
+	if index == max*max {
+		return true
+	}
+	pos := datatypes.Position{X: index / max, Y: index % max}
+	for _, k := range rng.Perm(max) {
+		if board.PeerCandidates(pos)[k+1] {
+			board.Givens[pos.X][pos.Y] = k + 1
+			if fillCells(rng, board, index+1) {
+				return true
+			}
+		}
+	}
+	board.Givens[pos.X][pos.Y] = 0
+	return false
+}
+
+// removeValues removes the givens of the board in a random order, keeping those without which the solution is not unique,
+// and stops at minGivens givens.
+func removeValues(rng *rand.Rand, board *datatypes.Board, minGivens int) *datatypes.Board {
+	givens := max * max
+	for _, k := range rng.Perm(max * max) {
+		if givens <= minGivens {
+			break
+		}
+		i, j := k/max, k%max
+		val := board.Givens[i][j]
+		board.Givens[i][j] = 0
+		if solutions, _, _ := solveBoard(board, 2); len(solutions) != 1 {
+			board.Givens[i][j] = val
+		} else {
+			givens--
+		}
+	}
+	return board
+}
@@ -0,0 +1,64 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/logic"
+)
+
+// hint prints the next step which can be found in the puzzle, with the technique and the reason. The placed values
+// of the puzzle are checked against the solution first, so that a wrong value is pointed out before any other hint.
+func hint(args []string) {
+	flags := newFlagSet("hint", "[file]", "Show the next step which can be found in a puzzle, with the technique and why.\n"+
+		"Placed values, like the state of a saved game, are checked against the solution first.")
+	in := addInputFlags(flags, "grid")
+	parseFlags(flags, args)
+	fmt.Println(nextHint(in.readOne(flags.Args())))
+}
+
+// nextHint returns the hint for the board: a repeated or wrong value, else the next step of the logic engine,
+// else the value of the solution in the empty cell with the fewest possible values.
+func nextHint(board *datatypes.Board) string {
+	if problems := conflicts(board); len(problems) > 0 {
+		return "Conflict: " + problems[0]
+	}
+	givens := datatypes.NewBoard()
+	givens.Givens = board.Givens
+	solutions, _, _ := solveBoard(givens, 2)
+	if len(solutions) == 0 {
+		return "The puzzle has no solution."
+	}
+	if len(solutions) == 1 {
+		for i := 0; i < max; i++ {
+			for j := 0; j < max; j++ {
+				pos := datatypes.Position{X: i, Y: j}
+				if board.Placed[i][j] > 0 && board.Placed[i][j] != solutions[0].Value(pos) {
+					return fmt.Sprintf("Mistake: %s is not %d.", cellName(pos), board.Placed[i][j])
+				}
+			}
+		}
+	}
+	if step := logic.NewState(board).Next(logic.Techniques); step != nil {
+		return step.String()
+	}
+	if len(solutions) > 1 {
+		return "No step can be found with the techniques, and the puzzle has more than one solution."
+	}
+	best, fewest := datatypes.Position{}, max+1
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if candidates := board.PeerCandidates(pos); board.Value(pos) == 0 && len(candidates) < fewest {
+				best, fewest = pos, len(candidates)
+			}
+		}
+	}
+	if fewest > max {
+		return "The puzzle is solved."
+	}
+	return fmt.Sprintf("No step can be found with the techniques. %s = %d in the solution.", cellName(best), solutions[0].Value(best))
+}
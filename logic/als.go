@@ -0,0 +1,291 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// MaxALSSize is the largest number of cells of an almost locked set. Larger sets are not listed,
+// which keeps the search fast. Programs using the package can change it.
+var MaxALSSize = 5
+
+// ALS is an almost locked set: cells of a unit which hold one candidate more than their number. If one of
+// the candidates is removed, the other candidates are all in the set.
+type ALS struct {
+	Cells  []datatypes.Position
+	Digits []int
+}
+
+// String returns the digits and the cells of the set, like (123)r1c12, or (123)r1c1,r2c3 for cells of a block.
+func (set ALS) String() string {
+	var digits string
+	for _, digit := range set.Digits {
+		digits += fmt.Sprint(digit)
+	}
+	return fmt.Sprintf("(%s)%s", digits, strings.ReplaceAll(groupName(set.Cells), ", ", ","))
+}
+
+// has returns true if the digit is a candidate of the set.
+func (set ALS) has(digit int) bool {
+	return containsInt(set.Digits, digit)
+}
+
+// digitCells returns the cells of the set which have the digit as a candidate.
+func (set ALS) digitCells(s *State, digit int) []datatypes.Position {
+	var cells []datatypes.Position
+	for _, pos := range set.Cells {
+		if s.Has(pos, digit) {
+			cells = append(cells, pos)
+		}
+	}
+	return cells
+}
+
+// overlaps returns true if the sets share a cell.
+func (set ALS) overlaps(other ALS) bool {
+	for _, pos := range set.Cells {
+		if containsPos(other.Cells, pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// almostLockedSets returns the almost locked sets of the units, with at most MaxALSSize cells, each once.
+func (s *State) almostLockedSets() []ALS {
+	var sets []ALS
+	found := make(map[string]bool)
+	for _, unit := range units {
+		var empty []datatypes.Position
+		for _, pos := range unit.Cells {
+			if s.Value(pos) == 0 {
+				empty = append(empty, pos)
+			}
+		}
+		for size := 1; size <= MaxALSSize && size < len(empty); size++ {
+			combinations(len(empty), size, func(indexes []int) bool {
+				set := ALS{}
+				for _, index := range indexes {
+					set.Cells = append(set.Cells, empty[index])
+					for _, digit := range s.Candidates(empty[index]) {
+						if !set.has(digit) {
+							set.Digits = append(set.Digits, digit)
+						}
+					}
+					if len(set.Digits) > size+1 {
+						return false
+					}
+				}
+				if set.Digits = sortInts(set.Digits); len(set.Digits) == size+1 && !found[set.String()] {
+					found[set.String()] = true
+					sets = append(sets, set)
+				}
+				return false
+			})
+		}
+	}
+	return sets
+}
+
+// restricted returns true if the digit is a restricted common candidate of the sets, which do not overlap:
+// each cell of the digit in one set sees each cell of the digit in the other set, so the digit is in one set at most.
+func (s *State) restricted(first ALS, second ALS, digit int) bool {
+	if !first.has(digit) || !second.has(digit) {
+		return false
+	}
+	for _, pos := range first.digitCells(s, digit) {
+		for _, other := range second.digitCells(s, digit) {
+			if !sees(pos, other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// alsEliminations returns the candidates of the digit in the cells which see all the cells of the digit in the sets,
+// outside the sets and the other cells.
+func (s *State) alsEliminations(digit int, sets []ALS, others ...datatypes.Position) []Candidate {
+	var cells, pattern []datatypes.Position
+	for _, set := range sets {
+		cells = append(cells, set.digitCells(s, digit)...)
+		pattern = append(pattern, set.Cells...)
+	}
+	return s.eliminate(commonPeers(cells), []int{digit}, append(pattern, others...))
+}
+
+// alsXZ finds two almost locked sets with a restricted common candidate x. x is in one of the sets at most, so the other
+// set is locked: each common candidate z of the sets is in one of them, and is not in the cells which see all of its cells
+// in both sets.
+func alsXZ(s *State) *Step {
+	sets := s.almostLockedSets()
+	for k, first := range sets {
+		for _, second := range sets[k+1:] {
+			if first.overlaps(second) {
+				continue
+			}
+			for _, x := range first.Digits {
+				if !s.restricted(first, second, x) {
+					continue
+				}
+				for _, z := range first.Digits {
+					if z == x || !second.has(z) {
+						continue
+					}
+					eliminations := s.alsEliminations(z, []ALS{first, second})
+					if len(eliminations) == 0 {
+						continue
+					}
+					return alsStep([]ALS{first, second}, []int{x, z}, eliminations,
+						fmt.Sprintf("the almost locked sets %s and %s share the restricted common candidate %d, which is in one of them at most, "+
+							"so %d is in one of them: %d is not in the cells which see all of its cells in both sets: %s.",
+							first, second, x, z, z, eliminationNames(eliminations)))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// alsXYWing finds two almost locked sets A and B, each with a restricted common candidate with a third set C:
+// x with A and y with B. One of A and B is locked, as C cannot hold both x and y: each common candidate z of A and B
+// is in one of them, and is not in the cells which see all of its cells in both sets.
+func alsXYWing(s *State) *Step {
+	sets := s.almostLockedSets()
+	for _, pivot := range sets {
+		var linked []ALS
+		var restricted []int
+		for _, set := range sets {
+			if set.overlaps(pivot) {
+				continue
+			}
+			for _, digit := range pivot.Digits {
+				if s.restricted(set, pivot, digit) {
+					linked = append(linked, set)
+					restricted = append(restricted, digit)
+				}
+			}
+		}
+		for k, first := range linked {
+			for l, second := range linked[k+1:] {
+				x, y := restricted[k], restricted[k+1+l]
+				if x == y || second.overlaps(first) {
+					continue
+				}
+				for _, z := range first.Digits {
+					if z == x || z == y || !second.has(z) {
+						continue
+					}
+					eliminations := s.alsEliminations(z, []ALS{first, second}, pivot.Cells...)
+					if len(eliminations) == 0 {
+						continue
+					}
+					return alsStep([]ALS{first, second, pivot}, []int{x, y, z}, eliminations,
+						fmt.Sprintf("the almost locked sets %s and %s are joined to %s by the restricted common candidates %d and %d, "+
+							"so one of them is locked, and %d is in one of them: %d is not in the cells which see all of its cells in both sets: %s.",
+							first, second, pivot, x, y, z, z, eliminationNames(eliminations)))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// deathBlossom finds a stem cell, and for each of its candidates an almost locked set, a petal, where all the cells of
+// that candidate see the stem. Whichever value the stem has, one of the petals is locked: each candidate z of all the petals,
+// which is not a candidate of the stem, is in one of them, and is not in the cells which see all of its cells in the petals.
+func deathBlossom(s *State) *Step {
+	sets := s.almostLockedSets()
+	for _, stem := range append(s.cellsWith(2), s.cellsWith(3)...) {
+		candidates := s.Candidates(stem)
+		petals := make([][]ALS, len(candidates))
+		for k, digit := range candidates {
+			for _, set := range sets {
+				if !set.has(digit) || containsPos(set.Cells, stem) {
+					continue
+				}
+				if s.restricted(set, ALS{Cells: []datatypes.Position{stem}, Digits: candidates}, digit) {
+					petals[k] = append(petals[k], set)
+				}
+			}
+		}
+		if step := s.blossom(stem, candidates, petals, nil); step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+// blossom chooses a petal of each candidate of the stem after the chosen petals, which does not overlap them,
+// and returns the step of the first choice which eliminates a candidate, or nil if there is none.
+func (s *State) blossom(stem datatypes.Position, candidates []int, petals [][]ALS, chosen []ALS) *Step {
+	if len(chosen) == len(candidates) {
+		for z := 1; z <= max; z++ {
+			if containsInt(candidates, z) {
+				continue
+			}
+			all := true
+			for _, petal := range chosen {
+				all = all && petal.has(z)
+			}
+			if !all {
+				continue
+			}
+			eliminations := s.alsEliminations(z, chosen, stem)
+			if len(eliminations) == 0 {
+				continue
+			}
+			var petalNames []string
+			for k, petal := range chosen {
+				petalNames = append(petalNames, fmt.Sprintf("the %d of %s", candidates[k], petal))
+			}
+			last := len(petalNames) - 1
+			step := alsStep(chosen, append(append([]int{}, candidates...), z), eliminations,
+				fmt.Sprintf("the stem %s (%s) sees %s and %s. Whichever value the stem has, one of the petals is locked, "+
+					"so %d is in one of them: %d is not in the cells which see all of its cells in the petals: %s.",
+					CellName(stem), digitNames(candidates), strings.Join(petalNames[:last], ", "), petalNames[last], z, z,
+					eliminationNames(eliminations)))
+			step.Pivot = []datatypes.Position{stem}
+			step.Cells = sortPositions(append(step.Cells, stem))
+			return step
+		}
+		return nil
+	}
+	for _, petal := range petals[len(chosen)] {
+		overlaps := false
+		for _, other := range chosen {
+			overlaps = overlaps || petal.overlaps(other)
+		}
+		if overlaps {
+			continue
+		}
+		if step := s.blossom(stem, candidates, petals, append(chosen, petal)); step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+// alsStep returns the step of the almost locked sets, with their cells, the digits and the eliminations.
+func alsStep(sets []ALS, digits []int, eliminations []Candidate, explanation string) *Step {
+	var cells []datatypes.Position
+	for _, set := range sets {
+		for _, pos := range set.Cells {
+			if !containsPos(cells, pos) {
+				cells = append(cells, pos)
+			}
+		}
+	}
+	return &Step{
+		Cells:        sortPositions(cells),
+		Digits:       digits,
+		Eliminations: eliminations,
+		Sets:         sets,
+		Explanation:  explanation,
+	}
+}
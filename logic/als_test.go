@@ -0,0 +1,21 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import "testing"
+
+// TestMaxALSSize verifies that the almost locked sets are limited to MaxALSSize cells.
+func TestMaxALSSize(t *testing.T) {
+	defer func(size int) { MaxALSSize = size }(MaxALSSize)
+	MaxALSSize = 1
+	sets := NewState(readPuzzle(t, ".71..42..8.4....7.2......8..9..71......92..6.....4.3..418.....2....36..19........")).almostLockedSets()
+	if len(sets) == 0 {
+		t.Error("Expected the cells with two candidates, got no set")
+	}
+	for _, set := range sets {
+		if len(set.Cells) != 1 || len(set.Digits) != 2 {
+			t.Error("Expected a cell with two candidates, got ", set)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// MaxChainLength is the largest number of links of a chain or a loop. Longer chains are not searched,
+// which keeps the search fast. Programs using the package can change it.
+var MaxChainLength = 16
+
+// candidateSet is a set of candidates, one bit per digit of each cell.
+type candidateSet [(max*max*max + 63) / 64]uint64
+
+// add adds the digit of the cell to the set.
+func (set *candidateSet) add(pos datatypes.Position, digit int) {
+	bit := (pos.X*max+pos.Y)*max + digit - 1
+	set[bit/64] |= 1 << uint(bit%64)
+}
+
+// intersect returns the candidates which are in all the sets.
+func intersect(sets ...candidateSet) candidateSet {
+	result := sets[0]
+	for _, set := range sets[1:] {
+		for k := range result {
+			result[k] &= set[k]
+		}
+	}
+	return result
+}
+
+// candidates returns the candidates of the set, cell by cell.
+func (set candidateSet) candidates() []Candidate {
+	var result []Candidate
+	for bit := 0; bit < max*max*max; bit++ {
+		if set[bit/64]&(1<<uint(bit%64)) != 0 {
+			cell := bit / max
+			result = append(result, Candidate{datatypes.Position{X: cell / max, Y: cell % max}, bit%max + 1})
+		}
+	}
+	return result
+}
+
+// chainGraph holds the nodes of the candidates of a state, with the strong and weak links between them.
+// kills are the candidates which cannot be true if each node is true. Every strong link is also a weak link.
+type chainGraph struct {
+	nodes  []Node
+	index  map[string]int
+	strong [][]int
+	weak   [][]int
+	kills  []candidateSet
+}
+
+// chainGraph returns the graph of the candidates of the state: a node for each candidate, for each group
+// of two or three cells of a block which hold a digit in one row or column, and for the groups of the strong links.
+func (s *State) chainGraph() *chainGraph {
+	g := &chainGraph{index: make(map[string]int)}
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			for _, digit := range s.Candidates(pos) {
+				g.addNode(Node{digit, []datatypes.Position{pos}})
+			}
+		}
+	}
+	for _, block := range blocks {
+		for digit := 1; digit <= max; digit++ {
+			cells, _ := s.digitCells(block, digit)
+			for _, line := range intersectingLines(block) {
+				if group := cellsIn(cells, line); len(group) >= 2 {
+					g.addNode(Node{digit, group})
+				}
+			}
+		}
+	}
+	for _, pos := range s.cellsWith(2) {
+		candidates := s.Candidates(pos)
+		g.addStrong(Node{candidates[0], []datatypes.Position{pos}}, Node{candidates[1], []datatypes.Position{pos}})
+	}
+	for _, unit := range units {
+		for digit := 1; digit <= max; digit++ {
+			if cells, placed := s.digitCells(unit, digit); !placed && len(cells) >= 2 {
+				for _, pair := range splitNodes(unit, cells) {
+					g.addStrong(Node{digit, pair[0]}, Node{digit, pair[1]})
+				}
+			}
+		}
+	}
+	for k, node := range g.nodes {
+		g.kills = append(g.kills, s.nodeKills(node))
+		for other := range g.nodes[:k] {
+			if excludes(node, g.nodes[other]) {
+				g.weak[k] = append(g.weak[k], other)
+				g.weak[other] = append(g.weak[other], k)
+			}
+		}
+	}
+	return g
+}
+
+// addNode adds the node to the graph, unless it is already there.
+func (g *chainGraph) addNode(node Node) {
+	if _, ok := g.index[node.String()]; ok {
+		return
+	}
+	g.index[node.String()] = len(g.nodes)
+	g.nodes = append(g.nodes, node)
+	g.strong = append(g.strong, nil)
+	g.weak = append(g.weak, nil)
+}
+
+// addStrong adds the strong link between the nodes, and the nodes, unless they are already there.
+func (g *chainGraph) addStrong(from Node, to Node) {
+	g.addNode(from)
+	g.addNode(to)
+	first, second := g.index[from.String()], g.index[to.String()]
+	if containsInt(g.strong[first], second) {
+		return
+	}
+	g.strong[first] = append(g.strong[first], second)
+	g.strong[second] = append(g.strong[second], first)
+}
+
+// nodeKills returns the candidates which cannot be true if the node is true: the digit in the cells which see
+// all the cells of the node, and the other digits of the cell of a node with one cell.
+func (s *State) nodeKills(node Node) candidateSet {
+	var kills candidateSet
+	for _, pos := range commonPeers(node.Cells) {
+		if s.Has(pos, node.Digit) {
+			kills.add(pos, node.Digit)
+		}
+	}
+	if len(node.Cells) == 1 {
+		for _, digit := range s.Candidates(node.Cells[0]) {
+			if digit != node.Digit {
+				kills.add(node.Cells[0], digit)
+			}
+		}
+	}
+	return kills
+}
+
+// excludes returns true if the nodes cannot both be true: two digits of the same cell, or the same digit
+// in different cells which all see each other.
+func excludes(first Node, second Node) bool {
+	if first.Digit != second.Digit {
+		return len(first.Cells) == 1 && len(second.Cells) == 1 && first.Cells[0] == second.Cells[0]
+	}
+	for _, pos := range first.Cells {
+		for _, other := range second.Cells {
+			if pos == other || !sees(pos, other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// intersectingLines returns the rows and the columns which cross the block.
+func intersectingLines(block Unit) []Unit {
+	return []Unit{rows[block.Cells[0].X], rows[block.Cells[3].X], rows[block.Cells[6].X],
+		columns[block.Cells[0].Y], columns[block.Cells[1].Y], columns[block.Cells[2].Y]}
+}
+
+// cellsIn returns the cells which are in the unit.
+func cellsIn(cells []datatypes.Position, unit Unit) []datatypes.Position {
+	var result []datatypes.Position
+	for _, pos := range cells {
+		if containsPos(unit.Cells[:], pos) {
+			result = append(result, pos)
+		}
+	}
+	return result
+}
+
+// splitNodes returns the ways to split the cells of a digit of the unit into two nodes, each a cell or a group
+// of cells of a block in one row or column. One of the two nodes is the digit.
+func splitNodes(unit Unit, cells []datatypes.Position) [][2][]datatypes.Position {
+	if len(cells) == 2 {
+		return [][2][]datatypes.Position{{cells[:1], cells[1:]}}
+	}
+	var parts []Unit
+	if unit.Kind == "block" {
+		parts = intersectingLines(unit)
+	} else {
+		parts = blocks
+	}
+	var result [][2][]datatypes.Position
+	for _, part := range parts {
+		in := cellsIn(cells, part)
+		var out []datatypes.Position
+		for _, pos := range cells {
+			if !containsPos(in, pos) {
+				out = append(out, pos)
+			}
+		}
+		if len(in) > 0 && len(out) > 0 && isNode(out) && (unit.Kind == "block" || len(in) > 1 || len(out) > 1) {
+			result = append(result, [2][]datatypes.Position{in, out})
+		}
+	}
+	return result
+}
+
+// isNode returns true if the cells are a cell, or a group of cells of a block in one row or column.
+func isNode(cells []datatypes.Position) bool {
+	sameRow, sameColumn := true, true
+	for _, pos := range cells[1:] {
+		if blockOf(pos) != blockOf(cells[0]) {
+			return false
+		}
+		sameRow = sameRow && pos.X == cells[0].X
+		sameColumn = sameColumn && pos.Y == cells[0].Y
+	}
+	return sameRow || sameColumn
+}
+
+// chainKind limits the nodes and the links of a chain. node returns true if the node can follow the start of the chain,
+// and link returns true if the link between the nodes can be used.
+type chainKind struct {
+	node func(start Node, node Node) bool
+	link func(from Node, to Node, strong bool) bool
+}
+
+// xChain allows the cells of the digit of the start, joined by conjugate pairs and by the cells which see each other.
+var xChain = chainKind{
+	node: func(start Node, node Node) bool { return len(node.Cells) == 1 && node.Digit == start.Digit },
+	link: func(from Node, to Node, strong bool) bool { return true },
+}
+
+// xyChain allows the candidates of cells, where the strong links are in the cells, and the weak links join the same digit
+// of different cells. As a chain starts and ends with a strong link, all its cells have two candidates.
+var xyChain = chainKind{
+	node: func(start Node, node Node) bool { return len(node.Cells) == 1 },
+	link: func(from Node, to Node, strong bool) bool { return strong == (from.Cells[0] == to.Cells[0]) },
+}
+
+// aic allows all the nodes and links, with the groups of cells.
+var aic = chainKind{
+	node: func(start Node, node Node) bool { return true },
+	link: func(from Node, to Node, strong bool) bool { return true },
+}
+
+// chains returns the technique which finds the shortest chain of the kind, with at most MaxChainLength links,
+// which alternates strong and weak links, and starts and ends with a strong link. One of the ends of the chain is true,
+// so the candidates which cannot be true with either end are eliminated. The technique also finds continuous loops,
+// where the last node has a weak link to the first node: one of the nodes of each weak link of the loop is true.
+func chains(kind chainKind) func(s *State) *Step {
+	return func(s *State) *Step {
+		g := s.chainGraph()
+		var best *Step
+		for start := range g.nodes {
+			if !kind.node(g.nodes[start], g.nodes[start]) {
+				continue
+			}
+			if step := s.searchChain(g, kind, start, best); step != nil {
+				best = step
+			}
+		}
+		return best
+	}
+}
+
+// searchChain searches the chains from the start node, from the shortest, and returns the step of the first chain
+// which eliminates candidates. Returns nil if there is none shorter than the best step.
+func (s *State) searchChain(g *chainGraph, kind chainKind, start int, best *Step) *Step {
+	var dist, parent [2][]int
+	for parity := range dist {
+		dist[parity], parent[parity] = make([]int, len(g.nodes)), make([]int, len(g.nodes))
+		for k := range g.nodes {
+			dist[parity][k] = -1
+		}
+	}
+	// A node is reached with parity 1 by a strong link, and with parity 0 by a weak link. The chain starts with a strong link.
+	dist[0][start] = 0
+	queue := [][2]int{{start, 0}}
+	for len(queue) > 0 {
+		node, parity := queue[0][0], queue[0][1]
+		queue = queue[1:]
+		length := dist[parity][node] + 1
+		if length > MaxChainLength || best != nil && length >= len(best.Links) {
+			return nil
+		}
+		next := g.strong[node]
+		if parity == 1 {
+			next = g.weak[node]
+		}
+		for _, other := range next {
+			if dist[1-parity][other] >= 0 || !kind.node(g.nodes[start], g.nodes[other]) || !kind.link(g.nodes[node], g.nodes[other], parity == 0) {
+				continue
+			}
+			dist[1-parity][other], parent[1-parity][other] = length, node
+			queue = append(queue, [2]int{other, 1 - parity})
+			if parity == 1 {
+				continue
+			}
+			path := g.chainPath(parent, start, other)
+			if path == nil {
+				continue
+			}
+			if step := s.chainEnds(g, path); step != nil {
+				return step
+			}
+			if length+1 <= MaxChainLength && other != start && containsInt(g.weak[other], start) && kind.link(g.nodes[other], g.nodes[start], false) {
+				if step := s.continuousLoop(g, append(path, start)); step != nil && (best == nil || len(step.Links) < len(best.Links)) {
+					return step
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// chainPath returns the nodes of the chain from the start to the end, which is reached by a strong link,
+// or nil if a node repeats.
+func (g *chainGraph) chainPath(parent [2][]int, start int, end int) []int {
+	path := []int{end}
+	for node, parity := end, 1; node != start || parity != 0; parity = 1 - parity {
+		node = parent[parity][node]
+		if containsInt(path, node) && node != start {
+			return nil
+		}
+		path = append([]int{node}, path...)
+	}
+	return path
+}
+
+// chainLinks returns the links between the nodes of the path, which alternate from a strong link.
+func (g *chainGraph) chainLinks(path []int) []Link {
+	var links []Link
+	for k := 1; k < len(path); k++ {
+		links = append(links, Link{g.nodes[path[k-1]], g.nodes[path[k]], k%2 == 1})
+	}
+	return links
+}
+
+// chainEnds returns the step of the chain of the path, which eliminates the candidates which cannot be true
+// with either end, or nil if there is none.
+func (s *State) chainEnds(g *chainGraph, path []int) *Step {
+	first, last := path[0], path[len(path)-1]
+	eliminations := intersect(g.kills[first], g.kills[last]).candidates()
+	if len(eliminations) == 0 {
+		return nil
+	}
+	links := g.chainLinks(path)
+	explanation := fmt.Sprintf("%s: one of the ends %s and %s is true, so the candidates which would rule out both ends are not: %s.",
+		chainName(links), g.nodes[first], g.nodes[last], eliminationNames(eliminations))
+	if first == last {
+		explanation = fmt.Sprintf("%s: if %s were not true, the chain would make it true, so it is true: %s.",
+			chainName(links), g.nodes[first], eliminationNames(eliminations))
+	}
+	return chainStepOf(links, eliminations, explanation)
+}
+
+// continuousLoop returns the step of the loop of the path, whose last node is the first node again, or nil if it
+// does not eliminate a candidate. One of the nodes of each weak link of the loop is true, so the candidates
+// which cannot be true with either node are not.
+func (s *State) continuousLoop(g *chainGraph, path []int) *Step {
+	var eliminated candidateSet
+	for k := 2; k < len(path); k += 2 {
+		kills := intersect(g.kills[path[k-1]], g.kills[path[k]])
+		for word := range eliminated {
+			eliminated[word] |= kills[word]
+		}
+	}
+	eliminations := eliminated.candidates()
+	if len(eliminations) == 0 {
+		return nil
+	}
+	links := g.chainLinks(path)
+	step := chainStepOf(links, eliminations, fmt.Sprintf("%s: the loop is continuous, so one of the nodes of each weak link is true, "+
+		"and the candidates which would rule out both nodes of a weak link are not: %s.", chainName(links), eliminationNames(eliminations)))
+	step.Technique = "Continuous Nice Loop"
+	return step
+}
+
+// chainStepOf returns the step of the links, with their cells and digits, and the eliminations.
+func chainStepOf(links []Link, eliminations []Candidate, explanation string) *Step {
+	var cells []datatypes.Position
+	var digits []int
+	for _, link := range links {
+		for _, node := range []Node{link.From, link.To} {
+			for _, pos := range node.Cells {
+				if !containsPos(cells, pos) {
+					cells = append(cells, pos)
+				}
+			}
+			if !containsInt(digits, node.Digit) {
+				digits = append(digits, node.Digit)
+			}
+		}
+	}
+	return &Step{
+		Cells:        sortPositions(cells),
+		Digits:       sortInts(digits),
+		Eliminations: eliminations,
+		Links:        links,
+		Explanation:  explanation,
+	}
+}
@@ -0,0 +1,64 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestMaxChainLength verifies that no chain is found when the chains are limited to no link.
+func TestMaxChainLength(t *testing.T) {
+	defer func(length int) { MaxChainLength = length }(MaxChainLength)
+	MaxChainLength = 0
+	result := Solve(readPuzzle(t, ".........4...53...5..67...9..........9....54.863.2......4.8.17..8.1...6...12.5.9."), techniquesNamed("AIC"))
+	if result.Solved || checkSteps(t, result, "937418256426953781518672439745891623192736548863524917654389172289147365371265894", "AIC") > 0 {
+		t.Error("Expected no chain, got ", result.Steps)
+	}
+}
+
+// TestAICStep verifies the Eureka notation of a chain with a group of cells, in the explanation and the JSON.
+func TestAICStep(t *testing.T) {
+	result := Solve(readPuzzle(t, ".....197.8.9....1.5.........4.8.2......6..35.3...4.2.......48..18.7.....26......."), techniquesNamed("AIC"))
+	for _, step := range result.Steps {
+		if step.Technique != "AIC" {
+			continue
+		}
+		expected := "AIC: (6)r2c7=r3c789-r3c6=r8c6: one of the ends (6)r2c7 and (6)r8c6 is true, " +
+			"so the candidates which would rule out both ends are not: r8c7<>6."
+		if step.String() != expected {
+			t.Error("Expected "+expected+", got ", step)
+		}
+		data, err := json.Marshal(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"links":["(6)r2c7=(6)r3c789","(6)r3c789-(6)r3c6","(6)r3c6=(6)r8c6"]`) {
+			t.Error("Expected the links in the JSON, got ", string(data))
+		}
+		return
+	}
+	t.Error("Expected an AIC")
+}
+
+// TestContinuousLoop verifies the eliminations of an X-Wing written as a continuous loop, which are the eliminations
+// of both of its weak links.
+func TestContinuousLoop(t *testing.T) {
+	s := NewState(readPuzzle(t, "7...8....6....982..............1...98...7.....92.6.3...8..4.......8..5.493.5.768."))
+	for step := s.Next(techniquesNamed()); step != nil; step = s.Next(techniquesNamed()) {
+		s.Apply(step)
+	}
+	g := s.chainGraph()
+	var path []int
+	for _, name := range []string{"(1)r2c3", "(1)r2c4", "(1)r7c4", "(1)r7c3", "(1)r2c3"} {
+		path = append(path, g.index[name])
+	}
+	step := s.continuousLoop(g, path)
+	expected := "Continuous Nice Loop: (1)r2c3=r2c4-r7c4=r7c3-r2c3: the loop is continuous, so one of the nodes of each weak link is true, " +
+		"and the candidates which would rule out both nodes of a weak link are not: r1c4<>1, r3c4<>1, r8c3<>1."
+	if step == nil || step.String() != expected {
+		t.Error("Expected "+expected+", got ", step)
+	}
+}
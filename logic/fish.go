@@ -0,0 +1,175 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// fishNames are the names of the fish by their size.
+var fishNames = map[int]string{2: "X-Wing", 3: "Swordfish", 4: "Jellyfish"}
+
+// fish returns the technique which finds size base rows where a digit is only in size cover columns, or the same with
+// columns as the base. The digit is then in each cover column at the intersection with a base line, and not in the other
+// cells of the cover columns. If finned is set, the digit of the base lines can also be in fins outside the cover lines,
+// all in one block: the digit is then only eliminated from the cells of the cover lines which are in the block of the fins.
+// A finned fish is sashimi if a base line has a single cell in the cover lines.
+func fish(size int, finned bool) func(s *State) *Step {
+	return func(s *State) *Step {
+		for digit := 1; digit <= max; digit++ {
+			for _, orientation := range [][]Unit{rows, columns} {
+				if step := s.findFish(digit, size, finned, orientation); step != nil {
+					return step
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// findFish finds a fish of the digit with the base lines from lines, which are the rows or the columns.
+func (s *State) findFish(digit int, size int, finned bool, lines []Unit) *Step {
+	covers := columns
+	if lines[0].Kind == "column" {
+		covers = rows
+	}
+	var candidates []Unit
+	for _, line := range lines {
+		if cells, placed := s.digitCells(line, digit); !placed && len(cells) >= 1 {
+			candidates = append(candidates, line)
+		}
+	}
+	var step *Step
+	combinations(len(candidates), size, func(indexes []int) bool {
+		base := make([]Unit, size)
+		var cells []datatypes.Position
+		var crossing []int
+		for k, index := range indexes {
+			base[k] = candidates[index]
+			lineCells, _ := s.digitCells(base[k], digit)
+			for _, pos := range lineCells {
+				cells = append(cells, pos)
+				if cross := crossIndex(pos, base[k]); !containsInt(crossing, cross) {
+					crossing = append(crossing, cross)
+				}
+			}
+		}
+		if !finned && len(crossing) == size {
+			cover := make([]Unit, size)
+			for k, cross := range sortInts(crossing) {
+				cover[k] = covers[cross]
+			}
+			step = s.fishStep(digit, base, cover, cells, nil)
+			return step != nil
+		}
+		if !finned || len(crossing) <= size {
+			return false
+		}
+		crossing = sortInts(crossing)
+		return combinations(len(crossing), size, func(coverIndexes []int) bool {
+			cover := make([]Unit, size)
+			for k, index := range coverIndexes {
+				cover[k] = covers[crossing[index]]
+			}
+			var fins []datatypes.Position
+			for _, pos := range cells {
+				if !inUnits(pos, cover) {
+					fins = append(fins, pos)
+				}
+			}
+			for _, fin := range fins {
+				if blockOf(fin) != blockOf(fins[0]) {
+					return false
+				}
+			}
+			step = s.fishStep(digit, base, cover, cells, fins)
+			return step != nil
+		})
+	})
+	return step
+}
+
+// fishStep returns the step of the fish, or nil if it does not eliminate a candidate. The candidates eliminated
+// are in the cover lines, outside the base lines, and in the block of the fins if there are fins.
+func (s *State) fishStep(digit int, base []Unit, cover []Unit, cells []datatypes.Position, fins []datatypes.Position) *Step {
+	var targets []datatypes.Position
+	for _, line := range cover {
+		for _, pos := range line.Cells {
+			if !inUnits(pos, base) && (len(fins) == 0 || blockOf(pos) == blockOf(fins[0])) {
+				targets = append(targets, pos)
+			}
+		}
+	}
+	eliminations := s.eliminate(targets, []int{digit}, nil)
+	if len(eliminations) == 0 {
+		return nil
+	}
+	name := fishNames[len(base)]
+	explanation := fmt.Sprintf("in %s, %d is only in %s, so it is not in the other cells of %s: %s.",
+		unitNames(base), digit, unitNames(cover), unitNames(cover), eliminationNames(eliminations))
+	if len(fins) > 0 {
+		name = "Finned " + name
+		for _, line := range base {
+			inCover := 0
+			for _, pos := range cells {
+				if inUnits(pos, []Unit{line}) && inUnits(pos, cover) {
+					inCover++
+				}
+			}
+			if inCover <= 1 {
+				name = "Sashimi " + fishNames[len(base)]
+			}
+		}
+		finNames := "fin " + cellNames(fins)
+		if len(fins) > 1 {
+			finNames = "fins " + cellNames(fins)
+		}
+		explanation = fmt.Sprintf("in %s, %d is only in %s, or in the %s. Either way, %d is not in the cells of %s which see the %s: %s.",
+			unitNames(base), digit, unitNames(cover), finNames, digit, unitNames(cover), strings.Fields(finNames)[0], eliminationNames(eliminations))
+	}
+	return &Step{
+		Technique:    name,
+		Cells:        cells,
+		Digits:       []int{digit},
+		Eliminations: eliminations,
+		Base:         base,
+		Cover:        cover,
+		Fins:         fins,
+		Explanation:  explanation,
+	}
+}
+
+// crossIndex returns the index of the line which crosses the line at the cell: the column of a cell of a row,
+// or the row of a cell of a column.
+func crossIndex(pos datatypes.Position, line Unit) int {
+	if line.Kind == "row" {
+		return pos.Y
+	}
+	return pos.X
+}
+
+// inUnits returns true if the cell is in one of the units.
+func inUnits(pos datatypes.Position, units []Unit) bool {
+	for _, unit := range units {
+		if containsPos(unit.Cells[:], pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitNames returns the names of lines of the same kind, like rows 2, 5 and 8.
+func unitNames(lines []Unit) string {
+	if len(lines) == 1 {
+		return lines[0].String()
+	}
+	var numbers []string
+	for _, line := range lines {
+		numbers = append(numbers, fmt.Sprint(line.Index+1))
+	}
+	return lines[0].Kind + "s " + strings.Join(numbers[:len(numbers)-1], ", ") + " and " + numbers[len(numbers)-1]
+}
@@ -0,0 +1,34 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestFishStep verifies the base lines, cover lines and fins of a finned X-Wing, and their JSON.
+func TestFishStep(t *testing.T) {
+	result := Solve(readPuzzle(t, "7...8....6....982..............1...98...7.....92.6.3...8..4.......8..5.493.5.768."), techniquesNamed("Finned X-Wing"))
+	for _, step := range result.Steps {
+		if step.Technique != "Finned X-Wing" {
+			continue
+		}
+		expected := "Finned X-Wing: in rows 1 and 4, 5 is only in columns 2 and 8, or in the fin r4c3. " +
+			"Either way, 5 is not in the cells of columns 2 and 8 which see the fin: r5c2<>5."
+		if step.String() != expected {
+			t.Error("Expected "+expected+", got ", step)
+		}
+		data, err := json.Marshal(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"base":["row 1","row 4"],"cover":["column 2","column 8"],"fins":["r4c3"]`) {
+			t.Error("Expected the base, cover and fins in the JSON, got ", string(data))
+		}
+		return
+	}
+	t.Error("Expected a finned X-Wing")
+}
@@ -0,0 +1,67 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// lockedCandidates finds a digit confined to the intersection of a block and a line. If the digit of a block
+// is only in one row or column, it is not in the other cells of that line (pointing). If the digit of a row
+// or column is only in one block, it is not in the other cells of that block (claiming).
+func lockedCandidates(s *State) *Step {
+	for _, unit := range units {
+		for digit := 1; digit <= max; digit++ {
+			cells, placed := s.digitCells(unit, digit)
+			if placed || len(cells) < 2 {
+				continue
+			}
+			for _, other := range intersectingUnits(unit, cells) {
+				eliminations := s.eliminate(other.Cells[:], []int{digit}, cells)
+				if len(eliminations) == 0 {
+					continue
+				}
+				kind := "Pointing"
+				if unit.Kind != "block" {
+					kind = "Claiming"
+				}
+				return &Step{
+					Technique:    "Locked Candidates (" + kind + ")",
+					Cells:        cells,
+					Digits:       []int{digit},
+					Eliminations: eliminations,
+					Explanation: fmt.Sprintf("in %s, %d is only in %s, so it is not in the other cells of %s: %s.",
+						unit, digit, other, other, eliminationNames(eliminations)),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// intersectingUnits returns the units other than the unit which hold all the cells: the row or column of cells
+// of a block, or the block of cells of a row or column.
+func intersectingUnits(unit Unit, cells []datatypes.Position) []Unit {
+	var result []Unit
+	first := cells[0]
+	sameRow, sameColumn, sameBlock := true, true, true
+	for _, pos := range cells[1:] {
+		sameRow = sameRow && pos.X == first.X
+		sameColumn = sameColumn && pos.Y == first.Y
+		sameBlock = sameBlock && blockOf(pos) == blockOf(first)
+	}
+	if unit.Kind == "block" {
+		if sameRow {
+			result = append(result, rows[first.X])
+		}
+		if sameColumn {
+			result = append(result, columns[first.Y])
+		}
+	} else if sameBlock {
+		result = append(result, blocks[blockOf(first)])
+	}
+	return result
+}
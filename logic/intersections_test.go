@@ -0,0 +1,20 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import "testing"
+
+// TestLockedCandidates verifies pointing in a puzzle where the singles are stuck, and that the puzzle is then solved.
+func TestLockedCandidates(t *testing.T) {
+	board := readPuzzle(t, "....45...8.....2.7..2.....4..6...3.2...1.....2.74..6..64..98...79...4..........3.")
+	stuck := Solve(board, Techniques[:2])
+	step := NewState(stuck.Board).Next(Techniques)
+	expected := "Locked Candidates (Pointing): in block 1, 5 is only in column 2, so it is not in the other cells of column 2: r4c2<>5, r5c2<>5, r6c2<>5."
+	if step == nil || step.String() != expected {
+		t.Error("Expected "+expected+", got ", step)
+	}
+	if result := Solve(board, Techniques); !result.Solved || result.Level != "medium" {
+		t.Error("Expected the puzzle to be solved with medium techniques, got ", result.Solved, result.Level)
+	}
+}
@@ -0,0 +1,375 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// Node is a digit in one cell, or in a group of cells of a block which are all in one row or column.
+type Node struct {
+	Digit int
+	Cells []datatypes.Position
+}
+
+// Link joins two nodes. A strong link means that one of the nodes is true: the digit is only in the two nodes of a unit
+// (a conjugate pair), or only in the two nodes of a cell. A weak link means that the nodes are not both true.
+type Link struct {
+	From   Node
+	To     Node
+	Strong bool
+}
+
+// String returns the node with the digit and the cells, like (4)r3c5, or (4)r3c56 for a group in a row.
+func (n Node) String() string {
+	return fmt.Sprintf("(%d)%s", n.Digit, groupName(n.Cells))
+}
+
+// String returns the link with = for a strong link and - for a weak link, like (4)r3c5=(4)r7c5.
+func (l Link) String() string {
+	return l.From.String() + linkSymbol(l.Strong) + l.To.String()
+}
+
+// linkSymbol returns = for a strong link, and - for a weak link.
+func linkSymbol(strong bool) string {
+	if strong {
+		return "="
+	}
+	return "-"
+}
+
+// groupName returns the name of the cells: r3c5 for a cell, r3c56 for cells of a row, r34c5 for cells of a column,
+// else the names of the cells separated by commas.
+func groupName(cells []datatypes.Position) string {
+	sameRow, sameColumn := true, true
+	for _, pos := range cells[1:] {
+		sameRow = sameRow && pos.X == cells[0].X
+		sameColumn = sameColumn && pos.Y == cells[0].Y
+	}
+	var rowNames, columnNames string
+	for _, pos := range cells {
+		rowNames += fmt.Sprint(pos.X + 1)
+		columnNames += fmt.Sprint(pos.Y + 1)
+	}
+	switch {
+	case sameRow:
+		return fmt.Sprintf("r%dc%s", cells[0].X+1, columnNames)
+	case sameColumn:
+		return fmt.Sprintf("r%sc%d", rowNames, cells[0].Y+1)
+	}
+	return cellNames(cells)
+}
+
+// chainName returns the links of a chain in Eureka notation, where each link starts at the end of the previous link,
+// like (4)r1c2=r1c5-r3c5=r3c8. Links in a cell are written in one term, like (1=2)r1c1-(2=3)r1c5, and the digit
+// of a term is only repeated when it changes.
+func chainName(links []Link) string {
+	var out strings.Builder
+	cells, term := links[0].From.Cells, []string{fmt.Sprint(links[0].From.Digit)}
+	digit := ""
+	writeTerm := func() {
+		if len(term) > 1 || term[0] != digit {
+			out.WriteString("(" + strings.Join(term, "") + ")")
+		}
+		out.WriteString(groupName(cells))
+		digit = term[len(term)-1]
+	}
+	for _, link := range links {
+		if groupName(link.To.Cells) == groupName(cells) {
+			term = append(term, linkSymbol(link.Strong), fmt.Sprint(link.To.Digit))
+			continue
+		}
+		writeTerm()
+		out.WriteString(linkSymbol(link.Strong))
+		cells, term = link.To.Cells, []string{fmt.Sprint(link.To.Digit)}
+	}
+	writeTerm()
+	return out.String()
+}
+
+// strongLink returns the strong link between two cells with the digit.
+func strongLink(digit int, from datatypes.Position, to datatypes.Position) Link {
+	return Link{Node{digit, []datatypes.Position{from}}, Node{digit, []datatypes.Position{to}}, true}
+}
+
+// weakLink returns the weak link between two groups of cells with the digit.
+func weakLink(digit int, from []datatypes.Position, to []datatypes.Position) Link {
+	return Link{Node{digit, from}, Node{digit, to}, false}
+}
+
+// conjugatePairs returns the pairs of cells of the units where the digit is only in two cells.
+func (s *State) conjugatePairs(units []Unit, digit int) [][2]datatypes.Position {
+	var pairs [][2]datatypes.Position
+	for _, unit := range units {
+		if cells, placed := s.digitCells(unit, digit); !placed && len(cells) == 2 {
+			pairs = append(pairs, [2]datatypes.Position{cells[0], cells[1]})
+		}
+	}
+	return pairs
+}
+
+// skyscraper finds two conjugate pairs of a digit in two rows, with one end of each in the same column,
+// and the other ends in different columns. One of the other ends is the digit, so the digit is not in the cells
+// which see both of them. The same is found with columns instead of rows.
+func skyscraper(s *State) *Step {
+	for digit := 1; digit <= max; digit++ {
+		for _, lines := range [][]Unit{rows, columns} {
+			pairs := s.conjugatePairs(lines, digit)
+			for k, first := range pairs {
+				for _, second := range pairs[k+1:] {
+					for _, ends := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+						base, other := first[ends[0]], second[ends[1]]
+						firstEnd, secondEnd := first[1-ends[0]], second[1-ends[1]]
+						if crossIndex(base, lines[0]) != crossIndex(other, lines[0]) || crossIndex(firstEnd, lines[0]) == crossIndex(secondEnd, lines[0]) {
+							continue
+						}
+						links := []Link{strongLink(digit, firstEnd, base), weakLink(digit, []datatypes.Position{base}, []datatypes.Position{other}),
+							strongLink(digit, other, secondEnd)}
+						if step := s.chainStep(digit, links, firstEnd, secondEnd); step != nil {
+							return step
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// twoStringKite finds a conjugate pair of a digit in a row and one in a column, with one end of each in the same block.
+// One of the other ends is the digit, so the digit is not in the cells which see both of them.
+func twoStringKite(s *State) *Step {
+	for digit := 1; digit <= max; digit++ {
+		for _, row := range s.conjugatePairs(rows, digit) {
+			for _, column := range s.conjugatePairs(columns, digit) {
+				for _, ends := range [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}} {
+					rowIn, columnIn := row[ends[0]], column[ends[1]]
+					rowEnd, columnEnd := row[1-ends[0]], column[1-ends[1]]
+					block := blockOf(rowIn)
+					if blockOf(columnIn) != block || blockOf(rowEnd) == block || blockOf(columnEnd) == block || rowIn == columnIn {
+						continue
+					}
+					links := []Link{strongLink(digit, rowEnd, rowIn), weakLink(digit, []datatypes.Position{rowIn}, []datatypes.Position{columnIn}),
+						strongLink(digit, columnIn, columnEnd)}
+					if step := s.chainStep(digit, links, rowEnd, columnEnd); step != nil {
+						return step
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// emptyRectangle finds a block where a digit is only in one row and one column, but not in one of them alone,
+// and a conjugate pair of the digit in a column with one end in that row. If the other end is not the digit,
+// the digit of the block is in its column, so the digit is not in the cell of that column in the row of the other end.
+// The same is found with a conjugate pair in a row.
+func emptyRectangle(s *State) *Step {
+	for digit := 1; digit <= max; digit++ {
+		for _, block := range blocks {
+			cells, placed := s.digitCells(block, digit)
+			if placed || len(cells) < 2 {
+				continue
+			}
+			for _, row := range []int{block.Cells[0].X, block.Cells[3].X, block.Cells[6].X} {
+				for _, column := range []int{block.Cells[0].Y, block.Cells[1].Y, block.Cells[2].Y} {
+					if step := s.emptyRectangleStep(digit, block, cells, row, column); step != nil {
+						return step
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// emptyRectangleStep returns the step of the empty rectangle of the digit in the block, with the cells of the digit
+// in the row and the column, or nil if there is none.
+func (s *State) emptyRectangleStep(digit int, block Unit, cells []datatypes.Position, row int, column int) *Step {
+	var inRow, notInRow, inColumn, notInColumn []datatypes.Position
+	for _, pos := range cells {
+		if pos.X != row && pos.Y != column {
+			return nil
+		}
+		if pos.X == row {
+			inRow = append(inRow, pos)
+		} else {
+			notInRow = append(notInRow, pos)
+		}
+		if pos.Y == column {
+			inColumn = append(inColumn, pos)
+		} else {
+			notInColumn = append(notInColumn, pos)
+		}
+	}
+	if len(notInRow) == 0 || len(notInColumn) == 0 {
+		return nil
+	}
+	for _, pair := range s.conjugatePairs(columns, digit) {
+		for k, end := range pair {
+			other := pair[1-k]
+			if end.X != row || blockOf(end) == block.Index || other.X/3 == row/3 {
+				continue
+			}
+			links := []Link{strongLink(digit, other, end), weakLink(digit, []datatypes.Position{end}, inRow),
+				{Node{digit, inRow}, Node{digit, notInRow}, true}}
+			if step := s.chainStep(digit, links, other, notInRow...); step != nil {
+				return step
+			}
+		}
+	}
+	for _, pair := range s.conjugatePairs(rows, digit) {
+		for k, end := range pair {
+			other := pair[1-k]
+			if end.Y != column || blockOf(end) == block.Index || other.Y/3 == column/3 {
+				continue
+			}
+			links := []Link{strongLink(digit, other, end), weakLink(digit, []datatypes.Position{end}, inColumn),
+				{Node{digit, inColumn}, Node{digit, notInColumn}, true}}
+			if step := s.chainStep(digit, links, other, notInColumn...); step != nil {
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+// chainStep returns the step of a chain of links of the digit, which starts and ends with a strong link:
+// one of its ends is the digit, so the digit is not in the cells which see all the cells of both ends.
+// Returns nil if the chain does not eliminate a candidate.
+func (s *State) chainStep(digit int, links []Link, start datatypes.Position, end ...datatypes.Position) *Step {
+	ends := append([]datatypes.Position{start}, end...)
+	var cells []datatypes.Position
+	for _, link := range links {
+		for _, pos := range append(link.From.Cells, link.To.Cells...) {
+			if !containsPos(cells, pos) {
+				cells = append(cells, pos)
+			}
+		}
+	}
+	eliminations := s.eliminate(commonPeers(ends), []int{digit}, cells)
+	if len(eliminations) == 0 {
+		return nil
+	}
+	return &Step{
+		Cells:        cells,
+		Digits:       []int{digit},
+		Eliminations: eliminations,
+		Links:        links,
+		Explanation: fmt.Sprintf("%s: one of the ends %s and %s is %d, so %d is not in the cells which see both ends: %s.",
+			chainName(links), groupName(links[0].From.Cells), groupName(links[len(links)-1].To.Cells), digit, digit,
+			eliminationNames(eliminations)),
+	}
+}
+
+// simpleColoring colors the cells of a digit joined by conjugate pairs with two colors, so that the cells of each pair
+// have different colors. One of the colors is the digit. If two cells of the same color see each other, that color
+// is not the digit (color wrap). Else, a cell which sees cells of both colors is not the digit (color trap).
+func simpleColoring(s *State) *Step {
+	for digit := 1; digit <= max; digit++ {
+		pairs := s.conjugatePairs(units, digit)
+		colored := make(map[datatypes.Position]bool)
+		for _, pair := range pairs {
+			if colored[pair[0]] {
+				continue
+			}
+			colors, links := colorCells(pair[0], pairs, digit)
+			for pos := range colors {
+				colored[pos] = true
+			}
+			if step := s.coloringStep(digit, colors, links); step != nil {
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+// colorCells colors the cells joined to the first cell by the pairs, and returns the colors 0 and 1 of the cells,
+// and the links which join them in the order they were colored.
+func colorCells(first datatypes.Position, pairs [][2]datatypes.Position, digit int) (map[datatypes.Position]int, []Link) {
+	colors := map[datatypes.Position]int{first: 0}
+	var links []Link
+	queue := []datatypes.Position{first}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, pair := range pairs {
+			for k, end := range pair {
+				other := pair[1-k]
+				if _, ok := colors[other]; end != pos || ok {
+					continue
+				}
+				colors[other] = 1 - colors[pos]
+				links = append(links, strongLink(digit, pos, other))
+				queue = append(queue, other)
+			}
+		}
+	}
+	return colors, links
+}
+
+// coloringStep returns the step of a color wrap or a color trap of the colored cells, or nil if there is none.
+func (s *State) coloringStep(digit int, colors map[datatypes.Position]int, links []Link) *Step {
+	groups := make([][]datatypes.Position, 2)
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if color, ok := colors[pos]; ok {
+				groups[color] = append(groups[color], pos)
+			}
+		}
+	}
+	cells := append(append([]datatypes.Position{}, groups[0]...), groups[1]...)
+	sortPositions(cells)
+	step := &Step{Technique: "Simple Coloring", Cells: cells, Digits: []int{digit}, Links: links}
+	colorNames := []string{"blue", "green"}
+	coloring := fmt.Sprintf("the strong links of %d color %s blue and %s green", digit, cellNames(groups[0]), cellNames(groups[1]))
+	for color, group := range groups {
+		for k, first := range group {
+			for _, second := range group[k+1:] {
+				if !sees(first, second) {
+					continue
+				}
+				step.Technique = "Simple Coloring (Color Wrap)"
+				step.Eliminations = s.eliminate(group, []int{digit}, nil)
+				step.Explanation = fmt.Sprintf("%s. %s and %s are both %s and see each other, so the %s cells are not %d, and the %s cells are: %s.",
+					coloring, CellName(first), CellName(second), colorNames[color], colorNames[color], digit, colorNames[1-color],
+					eliminationNames(step.Eliminations))
+				return step
+			}
+		}
+	}
+	var targets []datatypes.Position
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if _, ok := colors[pos]; !ok && seesAny(pos, groups[0]) && seesAny(pos, groups[1]) {
+				targets = append(targets, pos)
+			}
+		}
+	}
+	step.Eliminations = s.eliminate(targets, []int{digit}, nil)
+	if len(step.Eliminations) == 0 {
+		return nil
+	}
+	step.Technique = "Simple Coloring (Color Trap)"
+	step.Explanation = fmt.Sprintf("%s. The cells of one color are %d, so %d is not in the cells which see both colors: %s.", coloring,
+		digit, digit, eliminationNames(step.Eliminations))
+	return step
+}
+
+// seesAny returns true if the cell sees one of the cells.
+func seesAny(pos datatypes.Position, cells []datatypes.Position) bool {
+	for _, cell := range cells {
+		if cell != pos && sees(cell, pos) {
+			return true
+		}
+	}
+	return false
+}
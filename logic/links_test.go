@@ -0,0 +1,34 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestChainStep verifies the chain of an empty rectangle with a group of cells, in the explanation and the JSON.
+func TestChainStep(t *testing.T) {
+	result := Solve(readPuzzle(t, ".......984...2...6.79...3.....47..3........4...63...12317.9.....5.14..6..8......."), techniquesNamed("Empty Rectangle"))
+	for _, step := range result.Steps {
+		if step.Technique != "Empty Rectangle" {
+			continue
+		}
+		expected := "Empty Rectangle: (5)r4c9=r3c9-r3c1=r12c3: one of the ends r4c9 and r12c3 is 5, " +
+			"so 5 is not in the cells which see both ends: r4c3<>5."
+		if step.String() != expected {
+			t.Error("Expected "+expected+", got ", step)
+		}
+		data, err := json.Marshal(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"links":["(5)r4c9=(5)r3c9","(5)r3c9-(5)r3c1","(5)r3c1=(5)r12c3"]`) {
+			t.Error("Expected the links in the JSON, got ", string(data))
+		}
+		return
+	}
+	t.Error("Expected an empty rectangle")
+}
@@ -0,0 +1,450 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+// Package logic solves sudoku puzzles with the techniques of human solvers, one step at a time, and records each step
+// so that the solving path can be printed, serialized, explained as hints or rated. It never guesses:
+// the solver stops when no technique finds a step.
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const max int = 9
+
+// Levels are the difficulty levels of the techniques, from the easiest.
+var Levels = []string{"easy", "medium", "hard", "expert"}
+
+// Technique is a way of finding steps. find returns nil if the technique does not apply to the state.
+type Technique struct {
+	Name  string
+	Level string
+	find  func(s *State) *Step
+}
+
+// Techniques are the techniques of the solver, from the easiest. Each step is found by the first technique which applies.
+var Techniques = []Technique{
+	{"Naked Single", "easy", nakedSingle},
+	{"Hidden Single", "easy", hiddenSingle},
+	{"Locked Candidates", "medium", lockedCandidates},
+	{"Naked Pair", "medium", nakedSubset(2)},
+	{"Hidden Pair", "medium", hiddenSubset(2)},
+	{"Naked Triple", "hard", nakedSubset(3)},
+	{"Hidden Triple", "hard", hiddenSubset(3)},
+	{"X-Wing", "hard", fish(2, false)},
+	{"Naked Quad", "hard", nakedSubset(4)},
+	{"Hidden Quad", "hard", hiddenSubset(4)},
+	{"Swordfish", "hard", fish(3, false)},
+	{"Skyscraper", "hard", skyscraper},
+	{"2-String Kite", "hard", twoStringKite},
+	{"Empty Rectangle", "hard", emptyRectangle},
+	{"Finned X-Wing", "hard", fish(2, true)},
+	{"XY-Wing", "hard", xyWing},
+	{"XYZ-Wing", "hard", xyzWing},
+	{"W-Wing", "expert", wWing},
+	{"Simple Coloring", "expert", simpleColoring},
+	{"X-Chain", "expert", chains(xChain)},
+	{"XY-Chain", "expert", chains(xyChain)},
+	{"Jellyfish", "expert", fish(4, false)},
+	{"Finned Swordfish", "expert", fish(3, true)},
+	{"Finned Jellyfish", "expert", fish(4, true)},
+	{"ALS-XZ", "expert", alsXZ},
+	{"ALS-XY-Wing", "expert", alsXYWing},
+	{"Death Blossom", "expert", deathBlossom},
+	{"AIC", "expert", chains(aic)},
+}
+
+// Step is a deduction: the values placed, or the candidates eliminated, because of a pattern of cells and digits.
+// Base, Cover and Fins are the base lines, the cover lines and the fin cells of a fish. Pivot and Pincers are the cells of a wing:
+// Pivot is the pivot cell of an XY-Wing or an XYZ-Wing, the two cells of the strong link which joins the pincers of a W-Wing,
+// or the stem of a Death Blossom. Links are the links of the chain, or of the coloring, which leads to the eliminations.
+// Sets are the almost locked sets of the step.
+// Explanation tells the reason in words, naming the cells like r3c5.
+type Step struct {
+	Technique    string
+	Level        string
+	Cells        []datatypes.Position
+	Digits       []int
+	Placements   []Candidate
+	Eliminations []Candidate
+	Base         []Unit
+	Cover        []Unit
+	Fins         []datatypes.Position
+	Pivot        []datatypes.Position
+	Pincers      []datatypes.Position
+	Links        []Link
+	Sets         []ALS
+	Explanation  string
+}
+
+// Candidate is a digit in a cell.
+type Candidate struct {
+	Pos   datatypes.Position
+	Digit int
+}
+
+// Result is the outcome of solving a puzzle: the steps, whether the puzzle is solved, the level of the hardest step,
+// and the board with the values placed and the candidates left.
+type Result struct {
+	Steps  []Step           `json:"steps"`
+	Solved bool             `json:"solved"`
+	Level  string           `json:"level"`
+	Board  *datatypes.Board `json:"-"`
+}
+
+// Solve applies the techniques to the board one step at a time, until the puzzle is solved or no technique finds a step.
+// The candidates of the empty cells are the values which are not in their row, column or block.
+func Solve(board *datatypes.Board, techniques []Technique) *Result {
+	s := NewState(board)
+	result := &Result{}
+	for !s.Solved() {
+		step := s.Next(techniques)
+		if step == nil {
+			break
+		}
+		s.Apply(step)
+		result.Steps = append(result.Steps, *step)
+		if levelIndex(step.Level) > levelIndex(result.Level) {
+			result.Level = step.Level
+		}
+	}
+	result.Solved = s.Solved()
+	result.Board = s.Board(board)
+	return result
+}
+
+// levelIndex returns the index of the level in Levels, or -1 if it is not a level.
+func levelIndex(level string) int {
+	for k, l := range Levels {
+		if l == level {
+			return k
+		}
+	}
+	return -1
+}
+
+// State is a puzzle being solved: the value and the candidates of each cell. The candidates of a cell with a value
+// are only its value.
+type State struct {
+	cells [max][max]*datatypes.Value
+}
+
+// NewState creates the state of the board. The candidates of the empty cells are the values which are not
+// in their row, column or block.
+func NewState(board *datatypes.Board) *State {
+	s := &State{}
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if val := board.Value(pos); val > 0 {
+				s.cells[i][j] = datatypes.SetValue(val)
+			} else {
+				s.cells[i][j] = datatypes.InitValue()
+				s.cells[i][j].Possible = board.PeerCandidates(pos)
+			}
+		}
+	}
+	return s
+}
+
+// Value returns the value of the cell, or 0 if the cell is empty.
+func (s *State) Value(pos datatypes.Position) int {
+	return *s.cells[pos.X][pos.Y].Val
+}
+
+// Has returns true if the cell is empty, and the digit is one of its candidates.
+func (s *State) Has(pos datatypes.Position, digit int) bool {
+	cell := s.cells[pos.X][pos.Y]
+	return *cell.Val == 0 && cell.Possible[digit]
+}
+
+// Candidates returns the candidates of an empty cell in increasing order, or nil if the cell has a value.
+func (s *State) Candidates(pos datatypes.Position) []int {
+	cell := s.cells[pos.X][pos.Y]
+	if *cell.Val > 0 {
+		return nil
+	}
+	return datatypes.SortedValues(cell.Possible)
+}
+
+// Solved returns true if every cell has a value.
+func (s *State) Solved() bool {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if *s.cells[i][j].Val == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Next returns the step found by the first technique which applies, or nil if none applies.
+func (s *State) Next(techniques []Technique) *Step {
+	for _, technique := range techniques {
+		if step := technique.find(s); step != nil {
+			if step.Technique == "" {
+				step.Technique = technique.Name
+			}
+			step.Level = technique.Level
+			return step
+		}
+	}
+	return nil
+}
+
+// Apply places the values of the step, removing them from the candidates of their peers, and eliminates its candidates.
+func (s *State) Apply(step *Step) {
+	for _, placement := range step.Placements {
+		cell := s.cells[placement.Pos.X][placement.Pos.Y]
+		*cell.Val = placement.Digit
+		cell.Possible = map[int]bool{placement.Digit: true}
+		for _, peer := range peers[placement.Pos.X][placement.Pos.Y] {
+			if s.Value(peer) == 0 {
+				delete(s.cells[peer.X][peer.Y].Possible, placement.Digit)
+			}
+		}
+	}
+	for _, elimination := range step.Eliminations {
+		if s.Value(elimination.Pos) == 0 {
+			delete(s.cells[elimination.Pos.X][elimination.Pos.Y].Possible, elimination.Digit)
+		}
+	}
+}
+
+// Board returns the values of the state as a board: the givens of the puzzle, the other values as placed values,
+// and the candidates of the empty cells.
+func (s *State) Board(puzzle *datatypes.Board) *datatypes.Board {
+	board := datatypes.NewBoard()
+	board.Info = puzzle.Info
+	board.Givens = puzzle.Givens
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			switch {
+			case board.Givens[i][j] > 0:
+			case s.Value(pos) > 0:
+				board.Placed[i][j] = s.Value(pos)
+			default:
+				board.Candidates[i][j] = make(map[int]bool)
+				for _, digit := range s.Candidates(pos) {
+					board.Candidates[i][j][digit] = true
+				}
+			}
+		}
+	}
+	return board
+}
+
+// Unit is a row, a column or a block, with its cells. Index starts at 0, and blocks are numbered row by row.
+type Unit struct {
+	Kind  string
+	Index int
+	Cells [max]datatypes.Position
+}
+
+func (u Unit) String() string {
+	return fmt.Sprintf("%s %d", u.Kind, u.Index+1)
+}
+
+// units are the blocks, then the rows, then the columns. peers are the cells which share a unit with each cell.
+var units, blocks, rows, columns = makeUnits()
+var peers = makePeers()
+
+// makeUnits returns all the units, the blocks, the rows and the columns.
+func makeUnits() ([]Unit, []Unit, []Unit, []Unit) {
+	var all []Unit
+	for _, kind := range []string{"block", "row", "column"} {
+		for index := 0; index < max; index++ {
+			unit := Unit{Kind: kind, Index: index}
+			for k := 0; k < max; k++ {
+				switch kind {
+				case "block":
+					unit.Cells[k] = datatypes.Position{X: index/3*3 + k/3, Y: index%3*3 + k%3}
+				case "row":
+					unit.Cells[k] = datatypes.Position{X: index, Y: k}
+				case "column":
+					unit.Cells[k] = datatypes.Position{X: k, Y: index}
+				}
+			}
+			all = append(all, unit)
+		}
+	}
+	return all, all[:max], all[max : 2*max], all[2*max:]
+}
+
+// makePeers returns the peers of each cell, row by row.
+func makePeers() [max][max][]datatypes.Position {
+	var result [max][max][]datatypes.Position
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			first := datatypes.Position{X: i, Y: j}
+			for k := 0; k < max*max; k++ {
+				second := datatypes.Position{X: k / max, Y: k % max}
+				if second != first && sees(first, second) {
+					result[i][j] = append(result[i][j], second)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// sees returns true if the cells are in the same row, column or block.
+func sees(first datatypes.Position, second datatypes.Position) bool {
+	return first.X == second.X || first.Y == second.Y || (first.X/3 == second.X/3 && first.Y/3 == second.Y/3)
+}
+
+// blockOf returns the index of the block of the cell.
+func blockOf(pos datatypes.Position) int {
+	return pos.X/3*3 + pos.Y/3
+}
+
+// CellName returns the name of the cell by row and column, like r3c5.
+func CellName(pos datatypes.Position) string {
+	return fmt.Sprintf("r%dc%d", pos.X+1, pos.Y+1)
+}
+
+// cellNames returns the names of the cells, separated by commas.
+func cellNames(cells []datatypes.Position) string {
+	return strings.Join(positionNames(cells), ", ")
+}
+
+// positionNames returns the names of the cells.
+func positionNames(cells []datatypes.Position) []string {
+	var names []string
+	for _, pos := range cells {
+		names = append(names, CellName(pos))
+	}
+	return names
+}
+
+// digitNames returns the digits separated by slashes, like 3/7.
+func digitNames(digits []int) string {
+	var names []string
+	for _, digit := range digits {
+		names = append(names, fmt.Sprint(digit))
+	}
+	return strings.Join(names, "/")
+}
+
+// eliminationNames returns the eliminated candidates, like r3c5<>7, separated by commas.
+func eliminationNames(eliminations []Candidate) string {
+	var names []string
+	for _, elimination := range eliminations {
+		names = append(names, elimination.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// String returns the candidate as an elimination, like r3c5<>7.
+func (c Candidate) String() string {
+	return fmt.Sprintf("%s<>%d", CellName(c.Pos), c.Digit)
+}
+
+// String returns the technique and the explanation of the step.
+func (step Step) String() string {
+	return step.Technique + ": " + step.Explanation
+}
+
+// MarshalJSON writes the step with the cells named like r3c5, the placements like r3c5=7, and the eliminations like r3c5<>7.
+// The < and > are not escaped, if the encoder of the step does not escape HTML either.
+func (step Step) MarshalJSON() ([]byte, error) {
+	var placements, eliminations []string
+	for _, placement := range step.Placements {
+		placements = append(placements, fmt.Sprintf("%s=%d", CellName(placement.Pos), placement.Digit))
+	}
+	for _, elimination := range step.Eliminations {
+		eliminations = append(eliminations, elimination.String())
+	}
+	var base, cover, links, sets []string
+	for _, link := range step.Links {
+		links = append(links, link.String())
+	}
+	for _, set := range step.Sets {
+		sets = append(sets, set.String())
+	}
+	for _, unit := range step.Base {
+		base = append(base, unit.String())
+	}
+	for _, unit := range step.Cover {
+		cover = append(cover, unit.String())
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(struct {
+		Technique    string   `json:"technique"`
+		Level        string   `json:"level"`
+		Cells        []string `json:"cells,omitempty"`
+		Digits       []int    `json:"digits,omitempty"`
+		Placements   []string `json:"placements,omitempty"`
+		Eliminations []string `json:"eliminations,omitempty"`
+		Base         []string `json:"base,omitempty"`
+		Cover        []string `json:"cover,omitempty"`
+		Fins         []string `json:"fins,omitempty"`
+		Pivot        []string `json:"pivot,omitempty"`
+		Pincers      []string `json:"pincers,omitempty"`
+		Links        []string `json:"links,omitempty"`
+		Sets         []string `json:"sets,omitempty"`
+		Explanation  string   `json:"explanation"`
+	}{step.Technique, step.Level, positionNames(step.Cells), step.Digits, placements, eliminations, base, cover, positionNames(step.Fins),
+		positionNames(step.Pivot), positionNames(step.Pincers), links, sets, step.Explanation})
+	return bytes.TrimSpace(out.Bytes()), err
+}
+
+// eliminate returns the candidates of the digits in the cells, which are not in the pattern.
+func (s *State) eliminate(cells []datatypes.Position, digits []int, pattern []datatypes.Position) []Candidate {
+	var eliminations []Candidate
+	for _, pos := range cells {
+		if containsPos(pattern, pos) {
+			continue
+		}
+		for _, digit := range digits {
+			if s.Has(pos, digit) {
+				eliminations = append(eliminations, Candidate{pos, digit})
+			}
+		}
+	}
+	return eliminations
+}
+
+// containsPos returns true if the cell is one of the cells.
+func containsPos(cells []datatypes.Position, pos datatypes.Position) bool {
+	for _, cell := range cells {
+		if cell == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt returns true if the value is one of the values.
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortPositions sorts the cells row by row, and returns them.
+func sortPositions(cells []datatypes.Position) []datatypes.Position {
+	sort.Slice(cells, func(a, b int) bool {
+		return cells[a].X < cells[b].X || cells[a].X == cells[b].X && cells[a].Y < cells[b].Y
+	})
+	return cells
+}
+
+// sortInts sorts the values, and returns them.
+func sortInts(values []int) []int {
+	sort.Ints(values)
+	return values
+}
@@ -0,0 +1,166 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+)
+
+// readPuzzle returns the board of a puzzle of 81 cells, with . for an empty cell.
+func readPuzzle(t *testing.T, line string) *datatypes.Board {
+	board, err := formats.ReadLine(strings.NewReader(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return board
+}
+
+// techniquesNamed returns the singles, the locked candidates and the subsets, and the techniques with the names,
+// so that a test finds the same steps when other techniques are added.
+func techniquesNamed(names ...string) []Technique {
+	names = append(names, "Naked Single", "Hidden Single", "Locked Candidates", "Naked Pair", "Hidden Pair", "Naked Triple",
+		"Hidden Triple", "Naked Quad", "Hidden Quad")
+	var techniques []Technique
+	for _, technique := range Techniques {
+		for _, name := range names {
+			if technique.Name == name {
+				techniques = append(techniques, technique)
+			}
+		}
+	}
+	return techniques
+}
+
+// checkSteps verifies that each step of the result places values of the solution, and eliminates other digits,
+// and returns the number of steps of the technique.
+func checkSteps(t *testing.T, result *Result, solution string, technique string) int {
+	count := 0
+	for _, step := range result.Steps {
+		if step.Technique == technique {
+			count++
+		}
+		for _, placement := range step.Placements {
+			if digit := int(solution[placement.Pos.X*max+placement.Pos.Y] - '0'); placement.Digit != digit {
+				t.Error("Expected a placement of the solution, got ", step)
+			}
+		}
+		for _, elimination := range step.Eliminations {
+			if digit := int(solution[elimination.Pos.X*max+elimination.Pos.Y] - '0'); elimination.Digit == digit {
+				t.Error("Expected the elimination of a digit which is not in the solution, got ", step)
+			}
+		}
+	}
+	return count
+}
+
+// techniqueTests are the puzzles in which a technique finds a step, with their solutions. The puzzles are solved with
+// the basic techniques and the technique, or the techniques given, for a variant or a technique found after another.
+var techniqueTests = []struct {
+	technique, puzzle, solution string
+	techniques                  []string
+}{
+	{"X-Wing", "7...8....6....982..............1...98...7.....92.6.3...8..4.......8..5.493.5.768.",
+		"719286453645139827328754916457318269863972145192465378581643792276891534934527681", nil},
+	{"Finned X-Wing", "7...8....6....982..............1...98...7.....92.6.3...8..4.......8..5.493.5.768.",
+		"719286453645139827328754916457318269863972145192465378581643792276891534934527681", nil},
+	{"Swordfish", ".4....5......4.3..6....14...6.13.2...72........3.96..8...4.3....27.....6....6..1.",
+		"748329561291645387635781492869137254172854639453296178916473825327518946584962713", nil},
+	{"Sashimi Swordfish", "3..91.........2....9...5.46.5...3.2...17...8..8.59...7.........517.........3...75",
+		"348916752675432819192875346754683921961724583283591467836157294517249638429368175", []string{"Finned Swordfish"}},
+	{"XY-Wing", ".8...9........6.2...12....59..485.7...7..3...2..............5...24.5138...59...41",
+		"482579613593816724761234895936485172147623958258197436819342567624751389375968241", nil},
+	{"XYZ-Wing", "..2..8.........63..8.39..5..9.63....6......95.54..21...3..2.8..9.6.1.............",
+		"362158974519247638487396251791635482623481795854972163135729846946813527278564319", []string{"XYZ-Wing", "W-Wing"}},
+	{"W-Wing", ".4..3...6617.2.........7.9.3.5..9....9.84..2...6............1...62.7...4.......75",
+		"249138756617925438853467291385219647791846523426753819578394162962571384134682975", nil},
+	{"Skyscraper", "7.......526..8.........34..672..5.8..5..4.......21.....9......2...7..5......24..6",
+		"783492615264581973915673428672935184351847269849216357196358742428769531537124896", nil},
+	{"2-String Kite", ".71..42..8.4....7.2......8..9..71......92..6.....4.3..418.....2....36..19........",
+		"371584296864293175259167483695371824143928567782645319418759632527836941936412758", nil},
+	{"Empty Rectangle", ".......984...2...6.79...3.....47..3........4...63...12317.9.....5.14..6..8.......",
+		"265713498431829576879654321198472635523961847746385912317596284952148763684237159", nil},
+	{"Simple Coloring (Color Wrap)", "7...8....6....982..............1...98...7.....92.6.3...8..4.......8..5.493.5.768.",
+		"719286453645139827328754916457318269863972145192465378581643792276891534934527681", []string{"Simple Coloring"}},
+	{"Simple Coloring (Color Trap)", "9...6..28..7....5..6........527...1.3.........1...2...1.....6.2...9.4..7.24....9.",
+		"941567328287143956563289471452736819379851264816492735198375642635924187724618593", []string{"Simple Coloring"}},
+	{"X-Chain", "...6..7.....52...96.......51..45......7.3..2.3.....9......625.3..1.......2.7146..",
+		"235698741814527369679143285192456837457839126386271954748962513961385472523714698", nil},
+	{"XY-Chain", ".....197.8.9....1.5.........4.8.2......6..35.3...4.2.......48..18.7.....26.......",
+		"436251978879463512512978643645832197728619354391547286957324861183796425264185739", nil},
+	{"AIC", ".........4...53...5..67...9..........9....54.863.2......4.8.17..8.1...6...12.5.9.",
+		"937418256426953781518672439745891623192736548863524917654389172289147365371265894", nil},
+	{"ALS-XZ", ".71..42..8.4....7.2......8..9..71......92..6.....4.3..418.....2....36..19........",
+		"371584296864293175259167483695371824143928567782645319418759632527836941936412758", nil},
+	{"ALS-XY-Wing", ".71..42..8.4....7.2......8..9..71......92..6.....4.3..418.....2....36..19........",
+		"371584296864293175259167483695371824143928567782645319418759632527836941936412758", nil},
+	{"Death Blossom", ".71..42..8.4....7.2......8..9..71......92..6.....4.3..418.....2....36..19........",
+		"371584296864293175259167483695371824143928567782645319418759632527836941936412758", nil},
+}
+
+// TestTechniques verifies that each technique finds a step in its puzzle, and the placements and eliminations
+// of the steps against the solution.
+func TestTechniques(t *testing.T) {
+	for _, test := range techniqueTests {
+		techniques := test.techniques
+		if techniques == nil {
+			techniques = []string{test.technique}
+		}
+		if checkSteps(t, Solve(readPuzzle(t, test.puzzle), techniquesNamed(techniques...)), test.solution, test.technique) == 0 {
+			t.Error("Expected a step of " + test.technique)
+		}
+	}
+}
+
+// TestSolve verifies that singles solve an easy puzzle, and that the solver stops without guessing on a hard puzzle.
+func TestSolve(t *testing.T) {
+	result := Solve(readPuzzle(t, "8.2.....3376.5...1....82....6.1....77...4....1.......6...86..2.......1..2.8.1..49"), Techniques)
+	if !result.Solved || result.Level != "easy" {
+		t.Error("Expected an easy puzzle to be solved, got ", result.Solved, result.Level)
+	}
+	for _, step := range result.Steps {
+		if len(step.Placements) != 1 || len(step.Eliminations) != 0 {
+			t.Error("Expected a single placement, got ", step)
+		}
+	}
+	result = Solve(readPuzzle(t, "....45...8.....2.7..2.....4..6...3.2...1.....2.74..6..64..98...79...4..........3."), []Technique{Techniques[0], Techniques[1]})
+	if result.Solved || len(result.Steps) != 21 {
+		t.Error("Expected 21 steps before the singles are stuck, got ", len(result.Steps))
+	}
+	if candidates := result.Board.Candidates[0][0]; candidates == nil || candidates[4] || candidates[5] {
+		t.Error("Expected the candidates of r1c1 without 4 and 5, got ", candidates)
+	}
+}
+
+// TestSingles verifies the explanations of a naked and a hidden single.
+func TestSingles(t *testing.T) {
+	board := readPuzzle(t, "12345678.........................................................................")
+	step := NewState(board).Next(Techniques)
+	if step.String() != "Naked Single: r1c9 = 9, the only candidate left in the cell." {
+		t.Error("Expected a naked single, got ", step)
+	}
+	board = readPuzzle(t, "...1...........1...........1...........................1.........................")
+	step = NewState(board).Next(Techniques)
+	if step == nil || step.Explanation != "r3c3 = 1, the only cell of block 1 where 1 can go." {
+		t.Error("Expected a hidden single in block 1, got ", step)
+	}
+}
+
+// TestStepJSON verifies that the cells and the candidates of a step are written by name.
+func TestStepJSON(t *testing.T) {
+	step := Step{Technique: "Naked Single", Level: "easy", Cells: []datatypes.Position{{X: 0, Y: 8}}, Digits: []int{9},
+		Placements: []Candidate{{datatypes.Position{X: 0, Y: 8}, 9}}, Eliminations: []Candidate{{datatypes.Position{X: 2, Y: 4}, 7}}}
+	var out strings.Builder
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(step)
+	expected := `{"technique":"Naked Single","level":"easy","cells":["r1c9"],"digits":[9],"placements":["r1c9=9"],"eliminations":["r3c5<>7"],"explanation":""}` + "\n"
+	if out.String() != expected {
+		t.Error("Expected "+expected+", got ", out.String())
+	}
+}
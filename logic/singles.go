@@ -0,0 +1,61 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// nakedSingle finds an empty cell with a single candidate left, row by row.
+func nakedSingle(s *State) *Step {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if candidates := s.Candidates(pos); len(candidates) == 1 {
+				digit := candidates[0]
+				return &Step{
+					Cells:       []datatypes.Position{pos},
+					Digits:      []int{digit},
+					Placements:  []Candidate{{pos, digit}},
+					Explanation: fmt.Sprintf("%s = %d, the only candidate left in the cell.", CellName(pos), digit),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hiddenSingle finds a digit which can only go in one cell of a block, a row or a column.
+func hiddenSingle(s *State) *Step {
+	for _, unit := range units {
+		for digit := 1; digit <= max; digit++ {
+			cells, placed := s.digitCells(unit, digit)
+			if !placed && len(cells) == 1 {
+				return &Step{
+					Cells:       cells,
+					Digits:      []int{digit},
+					Placements:  []Candidate{{cells[0], digit}},
+					Explanation: fmt.Sprintf("%s = %d, the only cell of %s where %d can go.", CellName(cells[0]), digit, unit, digit),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// digitCells returns the empty cells of the unit which have the digit as a candidate, and true if the digit is a value of the unit.
+func (s *State) digitCells(unit Unit, digit int) ([]datatypes.Position, bool) {
+	var cells []datatypes.Position
+	for _, pos := range unit.Cells {
+		if s.Value(pos) == digit {
+			return nil, true
+		}
+		if s.Has(pos, digit) {
+			cells = append(cells, pos)
+		}
+	}
+	return cells, false
+}
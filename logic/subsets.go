@@ -0,0 +1,137 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// subsetNames are the names of the subsets by their size.
+var subsetNames = map[int]string{2: "pair", 3: "triple", 4: "quad"}
+
+// nakedSubset returns the technique which finds size cells of a unit whose candidates are size digits together.
+// The digits then go in these cells, and are not in the other cells of the unit.
+func nakedSubset(size int) func(s *State) *Step {
+	return func(s *State) *Step {
+		for _, unit := range units {
+			var cells []datatypes.Position
+			for _, pos := range unit.Cells {
+				if count := len(s.Candidates(pos)); count >= 2 && count <= size {
+					cells = append(cells, pos)
+				}
+			}
+			var step *Step
+			combinations(len(cells), size, func(indexes []int) bool {
+				subset := make([]datatypes.Position, size)
+				union := make(map[int]bool)
+				for k, index := range indexes {
+					subset[k] = cells[index]
+					for digit := range s.cells[cells[index].X][cells[index].Y].Possible {
+						union[digit] = true
+					}
+				}
+				if len(union) != size {
+					return false
+				}
+				digits := datatypes.SortedValues(union)
+				eliminations := s.eliminate(unit.Cells[:], digits, subset)
+				if len(eliminations) == 0 {
+					return false
+				}
+				step = &Step{
+					Cells:        subset,
+					Digits:       digits,
+					Eliminations: eliminations,
+					Explanation: fmt.Sprintf("%s hold only %s in %s, so these digits are not in the other cells of %s: %s.",
+						cellNames(subset), digitNames(digits), unit, unit, eliminationNames(eliminations)),
+				}
+				return true
+			})
+			if step != nil {
+				return step
+			}
+		}
+		return nil
+	}
+}
+
+// hiddenSubset returns the technique which finds size digits of a unit which are only in size cells together.
+// These cells then hold these digits, and none of their other candidates.
+func hiddenSubset(size int) func(s *State) *Step {
+	return func(s *State) *Step {
+		for _, unit := range units {
+			var digits []int
+			places := make(map[int][]datatypes.Position)
+			for digit := 1; digit <= max; digit++ {
+				cells, placed := s.digitCells(unit, digit)
+				if !placed && len(cells) >= 2 && len(cells) <= size {
+					digits = append(digits, digit)
+					places[digit] = cells
+				}
+			}
+			var step *Step
+			combinations(len(digits), size, func(indexes []int) bool {
+				subset := make([]int, size)
+				var cells []datatypes.Position
+				for k, index := range indexes {
+					subset[k] = digits[index]
+					for _, pos := range places[digits[index]] {
+						if !containsPos(cells, pos) {
+							cells = append(cells, pos)
+						}
+					}
+				}
+				if len(cells) != size {
+					return false
+				}
+				cells = sortPositions(cells)
+				var eliminations []Candidate
+				for _, pos := range cells {
+					for _, digit := range s.Candidates(pos) {
+						if !containsInt(subset, digit) {
+							eliminations = append(eliminations, Candidate{pos, digit})
+						}
+					}
+				}
+				if len(eliminations) == 0 {
+					return false
+				}
+				step = &Step{
+					Cells:        cells,
+					Digits:       subset,
+					Eliminations: eliminations,
+					Explanation: fmt.Sprintf("%s are only in %s in %s, so these cells hold no other digit: %s.",
+						digitNames(subset), cellNames(cells), unit, eliminationNames(eliminations)),
+				}
+				return true
+			})
+			if step != nil {
+				return step
+			}
+		}
+		return nil
+	}
+}
+
+// combinations calls visit with each set of k indexes out of n, in increasing order, until visit returns true.
+// Returns true if visit returned true.
+func combinations(n int, k int, visit func(indexes []int) bool) bool {
+	indexes := make([]int, k)
+	var next func(start int, depth int) bool
+	next = func(start int, depth int) bool {
+		if depth == k {
+			return visit(indexes)
+		}
+		for index := start; index <= n-(k-depth); index++ {
+			indexes[depth] = index
+			if next(index+1, depth+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return next(0, 0)
+}
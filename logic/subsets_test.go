@@ -0,0 +1,29 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import "testing"
+
+// TestSubsets verifies the naked and hidden subsets found in two puzzles, and their eliminations against the solutions.
+func TestSubsets(t *testing.T) {
+	tests := []struct {
+		puzzle, solution string
+		techniques       []string
+	}{
+		{"5.......9.......7...3..9.24...837....5...64...265...3.2....3.6...7.8......97..24.",
+			"564271389912348576873659124491837652358126497726594831245913768637482915189765243",
+			[]string{"Naked Triple", "Hidden Triple"}},
+		{".......736.4.37.95...6....2...45..3..2.9........8..5.75..........934....21...8...",
+			"952184673684237195137695482871452936325976814496813527548729361769341258213568749",
+			[]string{"Naked Pair", "Hidden Triple"}},
+	}
+	for _, test := range tests {
+		result := Solve(readPuzzle(t, test.puzzle), techniquesNamed())
+		for _, technique := range test.techniques {
+			if checkSteps(t, result, test.solution, technique) == 0 {
+				t.Error("Expected a step of " + technique)
+			}
+		}
+	}
+}
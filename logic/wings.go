@@ -0,0 +1,176 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package logic
+
+import (
+	"fmt"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// xyWing finds a pivot with the candidates x and y, which sees a pincer with x and z, and a pincer with y and z.
+// Whichever value the pivot has, one of the pincers is z: z is not in the cells which see both pincers.
+func xyWing(s *State) *Step {
+	for _, pivot := range s.cellsWith(2) {
+		pair := s.Candidates(pivot)
+		for _, first := range s.wingPincers(pivot, pair[0], pair[1]) {
+			digit := otherDigit(s.Candidates(first), pair[0])
+			for _, second := range s.wingPincers(pivot, pair[1], pair[0]) {
+				if !s.Has(second, digit) {
+					continue
+				}
+				pincers := []datatypes.Position{first, second}
+				eliminations := s.eliminate(commonPeers(pincers), []int{digit}, nil)
+				if len(eliminations) == 0 {
+					continue
+				}
+				return &Step{
+					Cells:        []datatypes.Position{pivot, first, second},
+					Digits:       []int{pair[0], pair[1], digit},
+					Eliminations: eliminations,
+					Pivot:        []datatypes.Position{pivot},
+					Pincers:      pincers,
+					Explanation: fmt.Sprintf("the pivot %s is %d or %d, so one of the pincers %s (%s) and %s (%s) is %d: %d is not in the cells which see both pincers: %s.",
+						CellName(pivot), pair[0], pair[1], CellName(first), digitNames(s.Candidates(first)), CellName(second), digitNames(s.Candidates(second)),
+						digit, digit, eliminationNames(eliminations)),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// xyzWing finds a pivot with the candidates x, y and z, which sees a pincer with x and z, and a pincer with y and z.
+// One of the three cells is z: z is not in the cells which see the pivot and both pincers.
+func xyzWing(s *State) *Step {
+	for _, pivot := range s.cellsWith(3) {
+		triple := s.Candidates(pivot)
+		for _, digit := range triple {
+			var pincers []datatypes.Position
+			for _, peer := range peers[pivot.X][pivot.Y] {
+				if candidates := s.Candidates(peer); len(candidates) == 2 && containsInt(candidates, digit) && containsInt(triple, otherDigit(candidates, digit)) {
+					pincers = append(pincers, peer)
+				}
+			}
+			for k, first := range pincers {
+				for _, second := range pincers[k+1:] {
+					if otherDigit(s.Candidates(first), digit) == otherDigit(s.Candidates(second), digit) {
+						continue
+					}
+					cells := []datatypes.Position{pivot, first, second}
+					eliminations := s.eliminate(commonPeers(cells), []int{digit}, nil)
+					if len(eliminations) == 0 {
+						continue
+					}
+					return &Step{
+						Cells:        cells,
+						Digits:       triple,
+						Eliminations: eliminations,
+						Pivot:        []datatypes.Position{pivot},
+						Pincers:      []datatypes.Position{first, second},
+						Explanation: fmt.Sprintf("one of the pivot %s (%s) and the pincers %s (%s) and %s (%s) is %d, so %d is not in the cells which see all three: %s.",
+							CellName(pivot), digitNames(triple), CellName(first), digitNames(s.Candidates(first)), CellName(second), digitNames(s.Candidates(second)),
+							digit, digit, eliminationNames(eliminations)),
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// wWing finds two pincers with the same candidates x and y, which do not see each other, joined by a strong link on x:
+// a unit where x is only in two cells, one seeing each pincer. One of the two cells is x, so one of the pincers is y:
+// y is not in the cells which see both pincers.
+func wWing(s *State) *Step {
+	cells := s.cellsWith(2)
+	for k, first := range cells {
+		for _, second := range cells[k+1:] {
+			pair := s.Candidates(first)
+			if sees(first, second) || digitNames(pair) != digitNames(s.Candidates(second)) {
+				continue
+			}
+			pincers := []datatypes.Position{first, second}
+			for _, digit := range pair {
+				other := otherDigit(pair, digit)
+				eliminations := s.eliminate(commonPeers(pincers), []int{other}, nil)
+				if len(eliminations) == 0 {
+					continue
+				}
+				for _, unit := range units {
+					link, placed := s.digitCells(unit, digit)
+					if placed || len(link) != 2 || containsPos(link, first) || containsPos(link, second) {
+						continue
+					}
+					if !(sees(link[0], first) && sees(link[1], second)) && !(sees(link[1], first) && sees(link[0], second)) {
+						continue
+					}
+					return &Step{
+						Cells:        []datatypes.Position{first, second, link[0], link[1]},
+						Digits:       pair,
+						Eliminations: eliminations,
+						Pivot:        link,
+						Pincers:      pincers,
+						Explanation: fmt.Sprintf("the pincers %s and %s are both %s, and %d is only in %s and %s in %s. One of these cells is %d, "+
+							"so one of the pincers is %d: %d is not in the cells which see both pincers: %s.",
+							CellName(first), CellName(second), digitNames(pair), digit, CellName(link[0]), CellName(link[1]), unit, digit,
+							other, other, eliminationNames(eliminations)),
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// wingPincers returns the peers of the pivot with two candidates: the digit, and a digit which is not a candidate of the pivot.
+// other is the second candidate of the pivot.
+func (s *State) wingPincers(pivot datatypes.Position, digit int, other int) []datatypes.Position {
+	var pincers []datatypes.Position
+	for _, peer := range peers[pivot.X][pivot.Y] {
+		if candidates := s.Candidates(peer); len(candidates) == 2 && containsInt(candidates, digit) && !containsInt(candidates, other) {
+			pincers = append(pincers, peer)
+		}
+	}
+	return pincers
+}
+
+// cellsWith returns the empty cells with the number of candidates, row by row.
+func (s *State) cellsWith(count int) []datatypes.Position {
+	var cells []datatypes.Position
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if len(s.Candidates(pos)) == count {
+				cells = append(cells, pos)
+			}
+		}
+	}
+	return cells
+}
+
+// commonPeers returns the cells which see all the cells, row by row.
+func commonPeers(cells []datatypes.Position) []datatypes.Position {
+	var common []datatypes.Position
+	for _, peer := range peers[cells[0].X][cells[0].Y] {
+		all := true
+		for _, pos := range cells[1:] {
+			if peer == pos || !sees(peer, pos) {
+				all = false
+			}
+		}
+		if all {
+			common = append(common, peer)
+		}
+	}
+	return common
+}
+
+// otherDigit returns the candidate of a pair which is not the digit.
+func otherDigit(pair []int, digit int) int {
+	if pair[0] == digit {
+		return pair[1]
+	}
+	return pair[0]
+}
@@ -0,0 +1,159 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+const textOutput, svgOutput, pngOutput, gifOutput, htmlOutput, jsonOutput = "text", "svg", "png", "gif", "html", "json"
+
+var outputFormats = map[string]bool{textOutput: true, svgOutput: true, pngOutput: true, gifOutput: true, htmlOutput: true, jsonOutput: true}
+
+// prettyPrint draws the grids with box-drawing borders, and colors when printing to a terminal.
+// puzzle holds the input values, so that they are drawn differently from the values found by the solver.
+var prettyPrint bool
+var puzzle *datatypes.Board
+
+// outputFormat is the format of the printed grids. The image formats hold a single grid:
+// the possible values after the initial elimination if printCandidates is set, else the first solution.
+// imageWritten is set once the image is written.
+// cellSize is the width of a cell in pixels in the image formats.
+// The gif format is an animation of the steps of the solver, with framesPerSecond frames in a second.
+// The html format is a report of the steps of the solver and of the solutions.
+var outputFormat string
+var imageWritten bool
+var cellSize int
+var framesPerSecond float64
+var animation *stepAnimation
+var report *solveReport
+
+// printGrid prints the values of the grid, or the possible values for the given iteration if candidates is set.
+func printGrid(grid *datatypes.Grid, iteration int, candidates bool) {
+	if outputFormat == gifOutput || outputFormat == htmlOutput {
+		return
+	}
+	if outputFormat != textOutput {
+		if !imageWritten && candidates == printCandidates {
+			imageWritten = true
+			writeImage(os.Stdout, datatypes.BoardFromGrid(grid, iteration, puzzle), candidates)
+		}
+		return
+	}
+	if candidates && iteration == 0 {
+		fmt.Println("Possible values after elimination:")
+	} else if candidates {
+		fmt.Println("Possible values at guess depth", iteration)
+	}
+	if !prettyPrint {
+		if candidates {
+			grid.PrintCandidates(iteration)
+		} else {
+			grid.Print()
+		}
+		return
+	}
+	opts := render.DefaultTextOptions(os.Stdout)
+	opts.Candidates = candidates
+	opts.Symbols = datatypes.Symbols
+	fmt.Println()
+	render.Text(os.Stdout, datatypes.BoardFromGrid(grid, iteration, puzzle), opts)
+	fmt.Println()
+}
+
+// writeImage writes the board in the image output format.
+func writeImage(w io.Writer, board *datatypes.Board, candidates bool) {
+	var err error
+	switch outputFormat {
+	case svgOutput:
+		err = render.SVG(w, board, render.SVGOptions{CellSize: cellSize, Candidates: candidates})
+	case pngOutput:
+		err = render.PNG(w, board, render.ImageOptions{CellSize: cellSize, Candidates: candidates})
+	}
+	if err != nil {
+		handleError("", err)
+	}
+}
+
+// startAnimation starts recording the steps of the solver, when the output is an animation.
+func startAnimation() {
+	if outputFormat != gifOutput {
+		return
+	}
+	if framesPerSecond <= 0 {
+		handleInvalidInput("frames per second must be more than 0", nil)
+	}
+	delay := int(100/framesPerSecond + 0.5)
+	if delay < 1 {
+		delay = 1
+	}
+	animation = newStepAnimation(puzzle, render.ImageOptions{CellSize: cellSize, Candidates: true}, delay)
+	recordStep = animation.record
+}
+
+// finishAnimation writes the animation of the recorded steps, when the output is an animation.
+func finishAnimation() {
+	if animation == nil {
+		return
+	}
+	recordStep = nil
+	animation.flush()
+	if animation.leftOut > 0 {
+		fmt.Fprintf(os.Stderr, "Animation: %d frames left out after the first %d\n", animation.leftOut, maxAnimationFrames)
+	}
+	if err := animation.animation.Encode(os.Stdout); err != nil {
+		handleError("", err)
+	}
+}
+
+// startReport starts recording the steps of the solver and the solutions, when the output is a report.
+// The solutions go to the report instead of being printed.
+func startReport() {
+	if outputFormat != htmlOutput {
+		return
+	}
+	report = newSolveReport(puzzle)
+	recordStep = report.record
+	onSolution = report.addSolution
+}
+
+// reportPropagation adds the possible values after the initial elimination to the report.
+func reportPropagation(grid *datatypes.Grid) {
+	if report != nil {
+		report.report.Candidates = datatypes.BoardFromGrid(grid, 0, puzzle)
+	}
+}
+
+// reportConflict records in the report that the elimination finds a conflict in the puzzle.
+func reportConflict() {
+	if report != nil {
+		report.report.Conflict = true
+	}
+}
+
+// finishReport writes the report, for the positions which were not set after the initial elimination,
+// with the total number of solutions as printed.
+func finishReport(positions map[datatypes.Position]bool, total string) {
+	if report == nil {
+		return
+	}
+	recordStep = nil
+	if err := render.HTMLReport(os.Stdout, report.finish(positions, total)); err != nil {
+		handleError("", err)
+	}
+}
+
+// summaryOutput returns where the number of solutions and the difficulty are printed.
+// These go to stderr when stdout holds an image.
+func summaryOutput() io.Writer {
+	if outputFormat != textOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
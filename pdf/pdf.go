@@ -0,0 +1,161 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+// Package pdf writes simple PDF documents with lines, rectangles and text in the standard Helvetica fonts.
+// The fonts are not embedded, as every PDF viewer provides them.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Document is a PDF document made of pages.
+type Document struct {
+	Title string
+	pages []*Page
+}
+
+// Page is a page of a document. Coordinates are in points (1/72 inch), from the top-left corner of the page.
+type Page struct {
+	Width   float64
+	Height  float64
+	content bytes.Buffer
+}
+
+// A4 page size in points.
+const A4Width, A4Height = 595.0, 842.0
+
+// New creates an empty Document.
+func New(title string) *Document {
+	return &Document{Title: title}
+}
+
+// AddPage adds a page of the given size to the document.
+func (doc *Document) AddPage(width float64, height float64) *Page {
+	page := &Page{Width: width, Height: height}
+	doc.pages = append(doc.pages, page)
+	return page
+}
+
+// Pages returns the pages of the document.
+func (doc *Document) Pages() []*Page {
+	return doc.pages
+}
+
+// Line draws a line from {x1, y1} to {x2, y2} in the gray level, from 0 for black to 1 for white.
+func (page *Page) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64, gray float64) {
+	fmt.Fprintf(&page.content, "%.3f G %.2f w 2 J %.2f %.2f m %.2f %.2f l S\n", gray, width, x1, page.Height-y1, x2, page.Height-y2)
+}
+
+// Rect fills the rectangle with top-left corner at {x, y} in the gray level.
+func (page *Page) Rect(x float64, y float64, width float64, height float64, gray float64) {
+	fmt.Fprintf(&page.content, "%.3f g %.2f %.2f %.2f %.2f re f\n", gray, x, page.Height-y-height, width, height)
+}
+
+// Text draws the text with its left end at x, and its baseline at y.
+// Characters which are not in the Latin-1 range are drawn as '?'.
+func (page *Page) Text(x float64, y float64, size float64, bold bool, gray float64, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&page.content, "BT %.3f g /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", gray, font, size, x, page.Height-y, escape(text))
+}
+
+// TextCentered draws the text centered at x, with its baseline at y.
+func (page *Page) TextCentered(x float64, y float64, size float64, bold bool, gray float64, text string) {
+	page.Text(x-TextWidth(text, size, bold)/2, y, size, bold, gray, text)
+}
+
+// TextWidth returns the width of the text in points, for the font size.
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range text {
+		if c >= ' ' && int(c-' ') < len(widths) {
+			total += widths[c-' ']
+		} else {
+			total += widths['?'-' ']
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// escape returns the text as the content of a PDF string, in the WinAnsi encoding.
+func escape(text string) string {
+	var out strings.Builder
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(c)
+		case c >= ' ' && c < 0x7f:
+			out.WriteRune(c)
+		case c >= 0xa0 && c <= 0xff:
+			fmt.Fprintf(&out, "\\%03o", c)
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+// WriteTo writes the document in the PDF format.
+func (doc *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+	// object starts a new object, numbered from 1 in the order of the calls.
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// objects 1 to 4 are the catalog, the page tree, the fonts, and the document information.
+	// Each page is followed by its content.
+	var kids []string
+	for index := range doc.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*index))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)))
+	object("<< /F1 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >> " +
+		"/F2 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >> >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (sudoku-solver) >>", escape(doc.Title)))
+	for index, page := range doc.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font 3 0 R >> /Contents %d 0 R >>",
+			page.Width, page.Height, 6+2*index))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.WriteTo(w)
+}
+
+// helveticaWidths and helveticaBoldWidths are the widths of the characters from ' ' to '~', in 1/1000 of the font size.
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = []int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
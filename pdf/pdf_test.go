@@ -0,0 +1,37 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestWriteTo verifies that the cross-reference table gives the offset of each object.
+func TestWriteTo(t *testing.T) {
+	doc := New("Book (1)")
+	doc.AddPage(A4Width, A4Height).Text(10, 20, 12, true, 0, "Sudoku")
+	doc.AddPage(A4Width, A4Height).Line(0, 0, 10, 10, 1, 0)
+	var out bytes.Buffer
+	doc.WriteTo(&out)
+	pdf := out.String()
+	start := strings.LastIndex(pdf, "startxref\n")
+	xref, _ := strconv.Atoi(strings.Fields(pdf[start+len("startxref\n"):])[0])
+	lines := strings.Split(pdf[xref:], "\n")
+	if lines[1] != "0 9" {
+		t.Fatal("Expected 9 objects, got ", lines[1])
+	}
+	for object := 1; object < 9; object++ {
+		offset, _ := strconv.Atoi(lines[2+object][:10])
+		if !strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj", object)) {
+			t.Error("Expected object ", object, " at offset ", offset)
+		}
+	}
+	if !strings.Contains(pdf, "/Title (Book \\(1\\))") || !strings.Contains(pdf, "/F2 12.00 Tf 10.00 822.00 Td (Sudoku) Tj") {
+		t.Error("Expected escaped title and text at the top of the page, got\n", pdf)
+	}
+}
@@ -0,0 +1,317 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+const playKeysHelp = `Keys:
+  arrows, h j k l  move the cursor
+  1-9              enter a value, or a pencil mark in pencil mode
+  0, space, x      clear the cell
+  p                switch pencil mode on or off
+  u, r             undo, redo
+  ?                show a hint
+  c                check the values against the solution
+  s                show the solution
+  q                quit`
+
+// ANSI styles of the cursor, of the values which repeat in a row, column or block, and of the mistakes found by a check.
+const (
+	ansiCursor   = "\x1b[7m"
+	ansiConflict = "\x1b[41m"
+	ansiMistake  = "\x1b[43m"
+)
+
+// play lets a player solve the puzzle in a full-screen terminal.
+func play(args []string) {
+	flags := newFlagSet("play", "[file]", "Play a puzzle in the terminal. Values placed in a saved game are kept.\n\n"+playKeysHelp)
+	in := addInputFlags(flags, "grid")
+	parseFlags(flags, args)
+	g := newGame(in.readOne(flags.Args()))
+	if err := g.playInTerminal(); err != nil {
+		handleError("", err)
+	}
+}
+
+// playInTerminal runs the game in the terminal, which is restored when the game ends.
+func (g *game) playInTerminal() error {
+	term, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer term.close()
+	return g.run(term)
+}
+
+// game is the state of a puzzle being played. solution is nil if the puzzle does not have a unique solution.
+// undo and redo hold the boards before the changes which can be undone, and after the changes which can be redone.
+// mistakes are the placed values which differ from the solution, shown after a check until the next change.
+type game struct {
+	board    *datatypes.Board
+	solution *datatypes.Board
+	cursor   datatypes.Position
+	pencil   bool
+	undo     []*datatypes.Board
+	redo     []*datatypes.Board
+	mistakes map[datatypes.Position]bool
+	message  string
+	quit     bool
+}
+
+// newGame creates a game for the board, and solves the givens to check the values of the player.
+func newGame(board *datatypes.Board) *game {
+	g := &game{board: board}
+	givens := datatypes.NewBoard()
+	givens.Givens = board.Givens
+	if len(conflicts(givens)) == 0 {
+		if solutions, _, _ := solveBoard(givens, 2); len(solutions) == 1 {
+			g.solution = solutions[0]
+		}
+	}
+	return g
+}
+
+// run draws the game and handles the keys read from the terminal, until the player quits.
+func (g *game) run(term io.ReadWriter) error {
+	buf := make([]byte, 16)
+	for !g.quit {
+		if _, err := io.WriteString(term, g.draw()); err != nil {
+			return err
+		}
+		n, err := term.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			g.handleKey(key)
+		}
+	}
+	return nil
+}
+
+// parseKeys returns the keys in the bytes read from the terminal. The arrows and delete keys are named by
+// "up", "down", "left", "right" and "delete", and the other keys are the characters read.
+func parseKeys(input []byte) []string {
+	sequences := map[string]string{"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left", "\x1b[3~": "delete"}
+	var keys []string
+	for len(input) > 0 {
+		found := false
+		for sequence, key := range sequences {
+			if bytes.HasPrefix(input, []byte(sequence)) {
+				keys = append(keys, key)
+				input = input[len(sequence):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			keys = append(keys, string(input[:1]))
+			input = input[1:]
+		}
+	}
+	return keys
+}
+
+// handleKey changes the game for the key.
+func (g *game) handleKey(key string) {
+	g.message = ""
+	moves := map[string]datatypes.Position{"up": {X: -1}, "k": {X: -1}, "down": {X: 1}, "j": {X: 1},
+		"left": {Y: -1}, "h": {Y: -1}, "right": {Y: 1}, "l": {Y: 1}}
+	if move, ok := moves[key]; ok {
+		g.cursor.X = (g.cursor.X + move.X + max) % max
+		g.cursor.Y = (g.cursor.Y + move.Y + max) % max
+		return
+	}
+	switch key {
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		g.enter(int(key[0] - '0'))
+	case "0", " ", "x", "delete", "\x7f", "\b":
+		g.clear()
+	case "p":
+		g.pencil = !g.pencil
+	case "u":
+		g.undoChange()
+	case "r":
+		g.redoChange()
+	case "?":
+		g.message = nextHint(g.board)
+	case "c":
+		g.check()
+	case "s":
+		g.showSolution()
+	case "q", "\x03":
+		g.quit = true
+	}
+}
+
+// enter places the value in the cell at the cursor, or switches its pencil mark in pencil mode.
+func (g *game) enter(val int) {
+	i, j := g.cursor.X, g.cursor.Y
+	if g.board.Givens[i][j] > 0 {
+		g.message = cellName(g.cursor) + " is given."
+		return
+	}
+	if g.pencil {
+		if g.board.Placed[i][j] > 0 {
+			g.message = "Clear " + cellName(g.cursor) + " before adding pencil marks."
+			return
+		}
+		g.change()
+		if g.board.Candidates[i][j] == nil {
+			g.board.Candidates[i][j] = make(map[int]bool)
+		}
+		if g.board.Candidates[i][j][val] {
+			delete(g.board.Candidates[i][j], val)
+		} else {
+			g.board.Candidates[i][j][val] = true
+		}
+		return
+	}
+	if g.board.Placed[i][j] == val {
+		return
+	}
+	g.change()
+	g.board.Placed[i][j] = val
+	if g.solved() {
+		g.message = "Solved!"
+	}
+}
+
+// clear removes the placed value and the pencil marks of the cell at the cursor.
+func (g *game) clear() {
+	i, j := g.cursor.X, g.cursor.Y
+	if g.board.Placed[i][j] == 0 && len(g.board.Candidates[i][j]) == 0 {
+		return
+	}
+	g.change()
+	g.board.Placed[i][j] = 0
+	g.board.Candidates[i][j] = nil
+}
+
+// change saves the board before a change, so that the change can be undone.
+func (g *game) change() {
+	g.undo = append(g.undo, g.board.Copy())
+	g.redo = nil
+	g.mistakes = nil
+}
+
+// undoChange restores the board before the last change.
+func (g *game) undoChange() {
+	if len(g.undo) == 0 {
+		g.message = "Nothing to undo."
+		return
+	}
+	g.redo = append(g.redo, g.board)
+	g.board = g.undo[len(g.undo)-1]
+	g.undo = g.undo[:len(g.undo)-1]
+	g.mistakes = nil
+}
+
+// redoChange restores the board after the last change which was undone.
+func (g *game) redoChange() {
+	if len(g.redo) == 0 {
+		g.message = "Nothing to redo."
+		return
+	}
+	g.undo = append(g.undo, g.board)
+	g.board = g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.mistakes = nil
+}
+
+// check marks the placed values which differ from the solution.
+func (g *game) check() {
+	if g.solution == nil {
+		g.message = "The values cannot be checked: the puzzle does not have a unique solution."
+		return
+	}
+	g.mistakes = make(map[datatypes.Position]bool)
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			pos := datatypes.Position{X: i, Y: j}
+			if g.board.Placed[i][j] > 0 && g.board.Placed[i][j] != g.solution.Value(pos) {
+				g.mistakes[pos] = true
+			}
+		}
+	}
+	g.message = fmt.Sprintf("%d mistakes.", len(g.mistakes))
+	if len(g.mistakes) == 1 {
+		g.message = "1 mistake."
+	}
+}
+
+// showSolution places the values of the solution in all the cells which are not given.
+func (g *game) showSolution() {
+	if g.solution == nil {
+		g.message = "The puzzle does not have a unique solution."
+		return
+	}
+	g.change()
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if g.board.Givens[i][j] == 0 {
+				g.board.Placed[i][j] = g.solution.Value(datatypes.Position{X: i, Y: j})
+				g.board.Candidates[i][j] = nil
+			}
+		}
+	}
+	g.message = "Solution shown. Press u to undo."
+}
+
+// solved returns true if all the cells are filled, and no value repeats in a row, column or block.
+func (g *game) solved() bool {
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if g.board.Value(datatypes.Position{X: i, Y: j}) == 0 {
+				return false
+			}
+		}
+	}
+	return len(conflicts(g.board)) == 0
+}
+
+// conflictCells returns the positions of the values which repeat in a row, column or block.
+func conflictCells(board *datatypes.Board) map[datatypes.Position]bool {
+	cells := make(map[datatypes.Position]bool)
+	for i := 0; i < max*max; i++ {
+		first := datatypes.Position{X: i / max, Y: i % max}
+		for j := i + 1; j < max*max; j++ {
+			second := datatypes.Position{X: j / max, Y: j % max}
+			if val := board.Value(first); val > 0 && board.Value(second) == val && sameUnit(first, second) {
+				cells[first], cells[second] = true, true
+			}
+		}
+	}
+	return cells
+}
+
+// draw returns the screen of the game: the board with the pencil marks and the highlighted cells, and the status lines.
+func (g *game) draw() string {
+	highlights := make(map[datatypes.Position]string)
+	for pos := range g.mistakes {
+		highlights[pos] = ansiMistake
+	}
+	for pos := range conflictCells(g.board) {
+		highlights[pos] = ansiConflict
+	}
+	highlights[g.cursor] += ansiCursor
+	var out strings.Builder
+	out.WriteString("\x1b[H\x1b[2J")
+	render.Text(&out, g.board, render.TextOptions{Color: true, Candidates: true, Highlights: highlights})
+	mode := "values"
+	if g.pencil {
+		mode = "pencil marks"
+	}
+	fmt.Fprintf(&out, "%s  Entering %s. Press ? for a hint, q to quit.\n", cellName(g.cursor), mode)
+	fmt.Fprintln(&out, g.message)
+	return out.String()
+}
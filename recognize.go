@@ -0,0 +1,104 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+
+	"github.com/wittyameta/sudoku-solver/recognize"
+)
+
+// recognizeImage reads a puzzle from a PNG or JPEG screenshot given as argument, or from stdin, and writes it to stdout.
+// The cells read with a low confidence are listed on stderr.
+// With -train, the digits of the screenshot are instead added to the templates file, using the values of the puzzle file.
+func recognizeImage(args []string) {
+	flags := newFlagSet("recognize", "[image]", "Read a puzzle from a PNG or JPEG screenshot of a cleanly drawn grid.\n"+
+		"With -train, the digits of the screenshot are added to the -templates file instead.")
+	to := addOutputFlag(flags, "grid")
+	templatesPath := flags.String("templates", "", "file of digit templates, used together with the built-in digits")
+	train := flags.String("train", "", "puzzle file with the values of the screenshot, to add its digits to the -templates file")
+	minConfidence := flags.Float64("min-confidence", 0.6, "list the cells read with a lower confidence, from 0 to 1")
+	parseFlags(flags, args)
+	var in io.Reader = os.Stdin
+	if flags.NArg() > 0 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			handleError("", err)
+		}
+		defer file.Close()
+		in = file
+	}
+	if *train != "" {
+		trainTemplates(in, *train, *templatesPath)
+		return
+	}
+	outFormat := lookupOutputFormat(*to)
+	templates := recognize.DefaultTemplates()
+	if *templatesPath != "" {
+		file, err := os.Open(*templatesPath)
+		if err != nil {
+			handleError("", err)
+		}
+		err = templates.Read(file)
+		file.Close()
+		if err != nil {
+			handleInvalidInput(*templatesPath+": "+err.Error(), nil)
+		}
+	}
+	result, err := recognize.Read(in, templates)
+	if err != nil {
+		handleInvalidInput("", err)
+	}
+	for i, row := range result.Confidence {
+		for j, confidence := range row {
+			if confidence < *minConfidence {
+				read := "empty"
+				if val := result.Board.Givens[i][j]; val > 0 {
+					read = fmt.Sprint(val)
+				}
+				fmt.Fprintf(os.Stderr, "warning: r%dc%d read as %s with confidence %.2f\n", i+1, j+1, read, confidence)
+			}
+		}
+	}
+	if err := outFormat.Write(os.Stdout, result.Board); err != nil {
+		handleError("", err)
+	}
+}
+
+// trainTemplates adds the digits of the screenshot to the templates file, using the values of the puzzle file.
+// The format of the puzzle is found from its extension, and is the grid format otherwise.
+func trainTemplates(in io.Reader, puzzlePath string, templatesPath string) {
+	if templatesPath == "" {
+		handleInvalidInput("-train needs a -templates file to add the digits to", nil)
+	}
+	board := (&puzzleInput{defaultFormat: "grid"}).readOne([]string{puzzlePath})
+	img, _, err := image.Decode(in)
+	if err != nil {
+		handleInvalidInput("", err)
+	}
+	templates := recognize.NewTemplates()
+	if file, err := os.Open(templatesPath); err == nil {
+		err = templates.Read(file)
+		file.Close()
+		if err != nil {
+			handleInvalidInput(templatesPath+": "+err.Error(), nil)
+		}
+	}
+	added, err := templates.Train(img, board)
+	if err != nil {
+		handleInvalidInput("", err)
+	}
+	out, err := os.Create(templatesPath)
+	if err != nil {
+		handleError("", err)
+	}
+	defer out.Close()
+	if _, err := templates.WriteTo(out); err != nil {
+		handleError("", err)
+	}
+	fmt.Fprintf(os.Stderr, "added %d templates to %s\n", added, templatesPath)
+}
@@ -0,0 +1,218 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+// Package recognize reads puzzles from screenshots of cleanly rendered grids, without any network or machine learning runtime.
+// The grid lines are found from the dark pixels of each row and column of the image, and the digits of the cells
+// are matched against glyph templates.
+package recognize
+
+import (
+	"errors"
+	"image"
+	// PNG and JPEG images are decoded by Read.
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const max int = 9
+
+// ErrNoGrid is returned when no grid of 10 evenly spaced lines is found in both directions.
+var ErrNoGrid = errors.New("no sudoku grid found in the image")
+
+// Result is a recognized puzzle. The digits found are the givens of the board.
+// Confidence is from 0 to 1 for each cell, and Grid is the rectangle of the grid in the image.
+type Result struct {
+	Board      *datatypes.Board
+	Confidence [max][max]float64
+	Grid       image.Rectangle
+}
+
+// Read decodes a PNG or JPEG image, and recognizes the puzzle in it.
+func Read(r io.Reader, templates *Templates) (*Result, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return Recognize(img, templates)
+}
+
+// Recognize finds the grid in the image, and matches the digit of each cell against the templates.
+func Recognize(img image.Image, templates *Templates) (*Result, error) {
+	gray := newGrayImage(img)
+	rows, columns, err := gray.findGrid()
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Board: datatypes.NewBoard(), Grid: image.Rect(columns[0].start, rows[0].start, columns[max].end, rows[max].end)}
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			g, emptiness := gray.cellGlyph(cellRect(rows, columns, i, j))
+			if g == nil {
+				result.Confidence[i][j] = emptiness
+				continue
+			}
+			result.Board.Givens[i][j], result.Confidence[i][j] = templates.match(g)
+		}
+	}
+	return result, nil
+}
+
+// grayImage is the luminance of an image, from 0 for black to 1 for white.
+type grayImage struct {
+	rect image.Rectangle
+	lum  []float64
+}
+
+// newGrayImage returns the luminance of the image, drawn over a white background.
+// Images with a dark background are inverted, so that the lines and digits are always darker than the background.
+func newGrayImage(img image.Image) grayImage {
+	rect := img.Bounds()
+	gray := grayImage{rect: rect, lum: make([]float64, rect.Dx()*rect.Dy())}
+	total := 0.0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			lum := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b) + float64(0xffff-a)) / 0xffff
+			gray.lum[gray.index(x, y)] = lum
+			total += lum
+		}
+	}
+	if total < float64(len(gray.lum))/2 {
+		for k := range gray.lum {
+			gray.lum[k] = 1 - gray.lum[k]
+		}
+	}
+	return gray
+}
+
+func (gray grayImage) index(x int, y int) int {
+	return (y-gray.rect.Min.Y)*gray.rect.Dx() + x - gray.rect.Min.X
+}
+
+func (gray grayImage) at(x int, y int) float64 {
+	return gray.lum[gray.index(x, y)]
+}
+
+// line is a run of rows, or of columns, of the image which are crossed by a grid line. end is after the last one.
+type line struct {
+	start int
+	end   int
+}
+
+func (l line) center() float64 {
+	return float64(l.start+l.end) / 2
+}
+
+// findGrid returns the 10 horizontal and the 10 vertical lines of the grid.
+func (gray grayImage) findGrid() (rows []line, columns []line, err error) {
+	width, height := gray.rect.Dx(), gray.rect.Dy()
+	rowRuns, columnRuns := make([]int, height), make([]int, width)
+	for y := 0; y < height; y++ {
+		rowRuns[y] = longestRun(width, func(k int) float64 { return gray.lum[y*width+k] })
+	}
+	for x := 0; x < width; x++ {
+		columnRuns[x] = longestRun(height, func(k int) float64 { return gray.lum[k*width+x] })
+	}
+	rows, columns = gridLines(rowRuns), gridLines(columnRuns)
+	if rows == nil || columns == nil {
+		return nil, nil, ErrNoGrid
+	}
+	for k := range rows {
+		rows[k].start += gray.rect.Min.Y
+		rows[k].end += gray.rect.Min.Y
+		columns[k].start += gray.rect.Min.X
+		columns[k].end += gray.rect.Min.X
+	}
+	return rows, columns, nil
+}
+
+// longestRun returns the length of the longest run of dark pixels among the n pixels of a row or a column.
+func longestRun(n int, lum func(k int) float64) int {
+	longest, run := 0, 0
+	for k := 0; k < n; k++ {
+		if lum(k) < 0.5 {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// gridLines returns the 10 evenly spaced lines of the grid, from the longest run of dark pixels of each row, or column.
+// Rows with long runs are lines, and the grid is the widest set of 10 evenly spaced lines.
+// Shorter runs are tried if no grid is found, for screenshots where the grid is small next to other dark areas.
+func gridLines(runs []int) []line {
+	longest := 0
+	for _, run := range runs {
+		if run > longest {
+			longest = run
+		}
+	}
+	for _, fraction := range []float64{0.5, 0.3, 0.15} {
+		var candidates []line
+		for k := 0; k < len(runs); k++ {
+			if float64(runs[k]) < fraction*float64(longest) || runs[k] < 2*max {
+				continue
+			}
+			if len(candidates) > 0 && candidates[len(candidates)-1].end == k {
+				candidates[len(candidates)-1].end++
+			} else {
+				candidates = append(candidates, line{k, k + 1})
+			}
+		}
+		if lines := evenlySpaced(candidates); lines != nil {
+			return lines
+		}
+	}
+	return nil
+}
+
+// evenlySpaced returns the widest set of 10 evenly spaced lines among the candidates, or nil if there is none.
+// Cells must be at least 8 pixels wide, and lines thinner than a quarter of a cell.
+func evenlySpaced(candidates []line) []line {
+	var best []line
+	for a := range candidates {
+		for b := len(candidates) - 1; b > a; b-- {
+			span := candidates[b].center() - candidates[a].center()
+			if span < 8*float64(max) || (best != nil && span <= best[max].center()-best[0].center()) {
+				continue
+			}
+			spacing := span / float64(max)
+			if candidates[a].end-candidates[a].start > int(spacing/4) {
+				continue
+			}
+			lines := []line{candidates[a]}
+			next := a + 1
+			for k := 1; k <= max && next <= b; k++ {
+				expected := candidates[a].center() + float64(k)*spacing
+				for next < b && candidates[next].center() < expected-spacing/8 {
+					next++
+				}
+				if math.Abs(candidates[next].center()-expected) > spacing/8 || candidates[next].end-candidates[next].start > int(spacing/4) {
+					break
+				}
+				lines = append(lines, candidates[next])
+				next++
+			}
+			if len(lines) == max+1 {
+				best = lines
+			}
+		}
+	}
+	return best
+}
+
+// cellRect returns the inside of the cell at {i, j}, between the grid lines, leaving out a border of 8% of the cell.
+func cellRect(rows []line, columns []line, i int, j int) image.Rectangle {
+	rect := image.Rect(columns[j].end, rows[i].end, columns[j+1].start, rows[i+1].start)
+	inset := (rect.Dx()*8 + 99) / 100
+	return rect.Inset(inset)
+}
@@ -0,0 +1,78 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package recognize
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// screenshot returns a JPEG image of the board drawn below a dark toolbar, with pencil marks in the empty cells.
+func screenshot(board *datatypes.Board) image.Image {
+	grid := render.Image(board, render.ImageOptions{CellSize: 40, Candidates: true})
+	img := image.NewRGBA(image.Rect(0, 0, grid.Bounds().Dx()+60, grid.Bounds().Dy()+120))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, img.Bounds().Dx(), 30), image.NewUniform(color.RGBA{0x30, 0x30, 0x30, 0xff}), image.Point{}, draw.Src)
+	draw.Draw(img, grid.Bounds().Add(image.Point{X: 30, Y: 60}), grid, image.Point{}, draw.Src)
+	var out bytes.Buffer
+	jpeg.Encode(&out, img, &jpeg.Options{Quality: 75})
+	decoded, _ := jpeg.Decode(&out)
+	return decoded
+}
+
+// TestRecognize verifies that the grid and the digits of a screenshot are found, and that pencil marks are ignored.
+func TestRecognize(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0], board.Givens[4][4], board.Givens[8][7] = 3, 8, 1
+	board.Placed[2][5] = 6
+	board.Candidates[0][1] = map[int]bool{2: true, 5: true, 9: true}
+	result, err := Recognize(screenshot(board), DefaultTemplates())
+	if err != nil {
+		t.Fatal("Expected no error, got ", err)
+	}
+	if result.Grid.Min.X > 32 || result.Grid.Min.Y > 62 || result.Grid.Min.X < 28 || result.Grid.Min.Y < 58 {
+		t.Error("Expected the grid at {30, 60}, got ", result.Grid)
+	}
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			expected := board.Value(datatypes.Position{X: i, Y: j})
+			if result.Board.Givens[i][j] != expected {
+				t.Error("Expected ", expected, " at ", i, j, ", got ", result.Board.Givens[i][j])
+			}
+			if result.Confidence[i][j] < 0.5 {
+				t.Error("Expected a confidence of at least 0.5 at ", i, j, ", got ", result.Confidence[i][j])
+			}
+		}
+	}
+	if _, err := Recognize(image.NewRGBA(image.Rect(0, 0, 100, 100)), DefaultTemplates()); err != ErrNoGrid {
+		t.Error("Expected ErrNoGrid for a blank image, got ", err)
+	}
+}
+
+// TestTrain verifies that trained templates are written and read back.
+func TestTrain(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[1][1], board.Givens[7][3] = 4, 7
+	templates := NewTemplates()
+	added, err := templates.Train(screenshot(board), board)
+	if err != nil || added != 2 {
+		t.Error("Expected 2 templates added, got ", added, err)
+	}
+	var out bytes.Buffer
+	templates.WriteTo(&out)
+	read := NewTemplates()
+	if err := read.Read(&out); err != nil || read.Count() != 2 {
+		t.Error("Expected 2 templates read, got ", read.Count(), err)
+	}
+	if err := read.Read(bytes.NewBufferString("4 123")); err == nil {
+		t.Error("Expected an error for a short template")
+	}
+}
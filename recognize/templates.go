@@ -0,0 +1,303 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package recognize
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// glyphWidth and glyphHeight are the size of a digit after it is scaled for matching.
+const glyphWidth, glyphHeight = 16, 24
+
+// glyph is a digit scaled to fit in glyphWidth x glyphHeight pixels, keeping its aspect. Each pixel is the ink from 0 to 1.
+type glyph [glyphHeight * glyphWidth]float64
+
+// Templates are the glyphs which the digits of the cells are matched against.
+// A digit can have several templates, e.g. for the regular and the bold digits of an app.
+type Templates struct {
+	glyphs [max + 1][]*glyph
+}
+
+// NewTemplates creates Templates without any glyph.
+func NewTemplates() *Templates {
+	return &Templates{}
+}
+
+// DefaultTemplates returns the templates of the built-in digits of the render package, in regular and bold weights.
+func DefaultTemplates() *Templates {
+	t := NewTemplates()
+	for val := 1; val <= max; val++ {
+		for _, weight := range []float64{0.1, 0.15} {
+			img := image.NewRGBA(image.Rect(0, 0, 48, 48))
+			draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+			render.DrawDigit(img, val, img.Bounds(), 32, weight, color.Black)
+			t.Add(val, img)
+		}
+	}
+	return t
+}
+
+// Count returns the number of templates.
+func (t *Templates) Count() int {
+	count := 0
+	for _, glyphs := range t.glyphs {
+		count += len(glyphs)
+	}
+	return count
+}
+
+// Add adds the digit drawn in the image as a template for val. The image should only hold the digit, on a plain background.
+func (t *Templates) Add(val int, img image.Image) error {
+	if val < 1 || val > max {
+		return fmt.Errorf("invalid digit %d", val)
+	}
+	gray := newGrayImage(img)
+	g, _ := gray.cellGlyph(gray.rect)
+	if g == nil {
+		return fmt.Errorf("no digit found in the image of %d", val)
+	}
+	t.glyphs[val] = append(t.glyphs[val], g)
+	return nil
+}
+
+// Train adds the digits of a screenshot as templates, using the values of the cells of the board.
+// It returns the number of templates added.
+func (t *Templates) Train(img image.Image, board *datatypes.Board) (int, error) {
+	gray := newGrayImage(img)
+	rows, columns, err := gray.findGrid()
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			val := board.Value(datatypes.Position{X: i, Y: j})
+			if val == 0 {
+				continue
+			}
+			if g, _ := gray.cellGlyph(cellRect(rows, columns, i, j)); g != nil {
+				t.glyphs[val] = append(t.glyphs[val], g)
+				added++
+			}
+		}
+	}
+	return added, nil
+}
+
+// match returns the digit with the template most correlated to the glyph, and the confidence of the match.
+// The confidence is low if the best correlation is low, or if the template of another digit is almost as close.
+func (t *Templates) match(g *glyph) (int, float64) {
+	best, bestScore, secondScore := 0, -1.0, -1.0
+	for val := 1; val <= max; val++ {
+		score := -1.0
+		for _, template := range t.glyphs[val] {
+			score = math.Max(score, correlation(g, template))
+		}
+		if score > bestScore {
+			best, bestScore, secondScore = val, score, bestScore
+		} else if score > secondScore {
+			secondScore = score
+		}
+	}
+	if best == 0 {
+		return 0, 0
+	}
+	return best, clamp(bestScore) * clamp((bestScore-secondScore)*8)
+}
+
+// correlation returns the correlation of the pixels of the glyphs, from -1 to 1.
+func correlation(a *glyph, b *glyph) float64 {
+	var meanA, meanB float64
+	for k := range a {
+		meanA += a[k]
+		meanB += b[k]
+	}
+	meanA /= float64(len(a))
+	meanB /= float64(len(b))
+	var product, squaresA, squaresB float64
+	for k := range a {
+		product += (a[k] - meanA) * (b[k] - meanB)
+		squaresA += (a[k] - meanA) * (a[k] - meanA)
+		squaresB += (b[k] - meanB) * (b[k] - meanB)
+	}
+	if squaresA == 0 || squaresB == 0 {
+		return 0
+	}
+	return product / math.Sqrt(squaresA*squaresB)
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// cellGlyph returns the glyph of the digit in the rectangle, or nil if it is empty.
+// Small marks, like pencil marks, are ignored. For an empty cell, it also returns the confidence that the cell is empty.
+func (gray grayImage) cellGlyph(rect image.Rectangle) (*glyph, float64) {
+	rect = rect.Intersect(gray.rect)
+	if rect.Empty() {
+		return nil, 0
+	}
+	background := gray.background(rect)
+	darkest := background
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			darkest = math.Min(darkest, gray.at(x, y))
+		}
+	}
+	contrast := background - darkest
+	if contrast < 0.3 {
+		return nil, 1 - contrast
+	}
+	ink := func(x int, y int) float64 {
+		return clamp((background - gray.at(x, y)) / contrast)
+	}
+	bounds := inkBounds(rect, func(x int, y int) bool { return ink(x, y) > 0.5 })
+	if bounds.Dy() < rect.Dy()*2/5 {
+		return nil, 1 - float64(bounds.Dy())/float64(rect.Dy())
+	}
+	return scaleGlyph(bounds, ink), 0
+}
+
+// background returns the most common luminance in the rectangle, rounded to 1/32.
+func (gray grayImage) background(rect image.Rectangle) float64 {
+	var counts [33]int
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			counts[int(math.Round(gray.at(x, y)*32))]++
+		}
+	}
+	common := 0
+	for level := range counts {
+		if counts[level] > counts[common] {
+			common = level
+		}
+	}
+	return float64(common) / 32
+}
+
+// inkBounds returns the bounds of the tallest group of connected ink pixels in the rectangle,
+// together with the groups which overlap it, for digits drawn in several parts.
+func inkBounds(rect image.Rectangle, isInk func(x int, y int) bool) image.Rectangle {
+	seen := make(map[image.Point]bool)
+	var groups []image.Rectangle
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			start := image.Point{X: x, Y: y}
+			if seen[start] || !isInk(x, y) {
+				continue
+			}
+			seen[start] = true
+			bounds := image.Rectangle{Min: start, Max: start.Add(image.Point{X: 1, Y: 1})}
+			stack := []image.Point{start}
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				bounds = bounds.Union(image.Rectangle{Min: p, Max: p.Add(image.Point{X: 1, Y: 1})})
+				for _, q := range []image.Point{{X: p.X - 1, Y: p.Y}, {X: p.X + 1, Y: p.Y}, {X: p.X, Y: p.Y - 1}, {X: p.X, Y: p.Y + 1}} {
+					if q.In(rect) && !seen[q] && isInk(q.X, q.Y) {
+						seen[q] = true
+						stack = append(stack, q)
+					}
+				}
+			}
+			groups = append(groups, bounds)
+		}
+	}
+	tallest := image.Rectangle{}
+	for _, group := range groups {
+		if group.Dy() > tallest.Dy() {
+			tallest = group
+		}
+	}
+	bounds := tallest
+	for _, group := range groups {
+		if group.Overlaps(tallest) {
+			bounds = bounds.Union(group)
+		}
+	}
+	return bounds
+}
+
+// scaleGlyph scales the ink inside the bounds to a glyph, keeping its aspect and centering it.
+// Each pixel of the glyph is the average of the ink of 3x3 points of the image.
+func scaleGlyph(bounds image.Rectangle, ink func(x int, y int) float64) *glyph {
+	scale := math.Min(float64(glyphHeight)/float64(bounds.Dy()), float64(glyphWidth)/float64(bounds.Dx()))
+	left := (float64(glyphWidth) - float64(bounds.Dx())*scale) / 2
+	top := (float64(glyphHeight) - float64(bounds.Dy())*scale) / 2
+	g := &glyph{}
+	for ty := 0; ty < glyphHeight; ty++ {
+		for tx := 0; tx < glyphWidth; tx++ {
+			total := 0.0
+			for sy := 0; sy < 3; sy++ {
+				for sx := 0; sx < 3; sx++ {
+					x := bounds.Min.X + int(math.Floor((float64(tx)+(float64(sx)+0.5)/3-left)/scale))
+					y := bounds.Min.Y + int(math.Floor((float64(ty)+(float64(sy)+0.5)/3-top)/scale))
+					if (image.Point{X: x, Y: y}).In(bounds) {
+						total += ink(x, y)
+					}
+				}
+			}
+			g[ty*glyphWidth+tx] = total / 9
+		}
+	}
+	return g
+}
+
+// WriteTo writes the templates as text. Each line is a digit followed by the ink of the pixels of its glyph, from 0 to 9.
+func (t *Templates) WriteTo(w io.Writer) (int64, error) {
+	var out strings.Builder
+	out.WriteString("# sudoku-solver digit templates\n")
+	for val, glyphs := range t.glyphs {
+		for _, g := range glyphs {
+			fmt.Fprintf(&out, "%d ", val)
+			for _, ink := range g {
+				out.WriteByte('0' + byte(math.Round(ink*9)))
+			}
+			out.WriteByte('\n')
+		}
+	}
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
+
+// Read adds the templates written by WriteTo. Lines starting with '#' are ignored.
+func (t *Templates) Read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		val, err := strconv.Atoi(fields[0])
+		if err != nil || val < 1 || val > max || len(fields) != 2 {
+			return &formats.ParseError{Line: lineNum, Msg: "expected a digit from 1 to 9, followed by the pixels of its glyph"}
+		}
+		if len(fields[1]) != len(glyph{}) {
+			return &formats.ParseError{Line: lineNum, Msg: fmt.Sprintf("expected %d pixels, found %d", len(glyph{}), len(fields[1]))}
+		}
+		g := &glyph{}
+		for k, c := range fields[1] {
+			if c < '0' || c > '9' {
+				return &formats.ParseError{Line: lineNum, Column: strings.Index(text, fields[1]) + k + 1, Msg: fmt.Sprintf("invalid pixel %q", c)}
+			}
+			g[k] = float64(c-'0') / 9
+		}
+		t.glyphs[val] = append(t.glyphs[val], g)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,127 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/pdf"
+)
+
+// BookPuzzle is a puzzle of a book, with the solution printed in the answers, and the label printed under the puzzle.
+type BookPuzzle struct {
+	Puzzle   *datatypes.Board
+	Solution *datatypes.Board
+	Label    string
+}
+
+// BookOptions controls the layout of a book.
+// PerPage is the number of puzzles on a page, and AnswersPerPage the number of solutions on a page of the answers.
+type BookOptions struct {
+	Title          string
+	PerPage        int
+	AnswersPerPage int
+}
+
+const bookMargin, bookHeader, bookFooter = 40.0, 50.0, 30.0
+
+// PDFBook writes the puzzles as an A4 PDF document, followed by their solutions.
+// Each puzzle is numbered, and its label is printed under it.
+func PDFBook(w io.Writer, puzzles []BookPuzzle, opts BookOptions) error {
+	if opts.PerPage < 1 || opts.AnswersPerPage < 1 {
+		return fmt.Errorf("the number of puzzles on a page must be at least 1")
+	}
+	doc := pdf.New(opts.Title)
+	for start := 0; start < len(puzzles); start += opts.PerPage {
+		page := bookPage(doc, opts.Title)
+		for index := start; index < start+opts.PerPage && index < len(puzzles); index++ {
+			x, y, size := bookSlot(index-start, opts.PerPage)
+			drawPDFGrid(page, puzzles[index].Puzzle, x, y, size)
+			label := fmt.Sprint(index + 1)
+			if puzzles[index].Label != "" {
+				label += ". " + puzzles[index].Label
+			}
+			page.TextCentered(x+size/2, y+size+size/18+10, math.Min(12, size/14), false, 0, label)
+		}
+	}
+	for start := 0; start < len(puzzles); start += opts.AnswersPerPage {
+		page := bookPage(doc, "Answers")
+		for index := start; index < start+opts.AnswersPerPage && index < len(puzzles); index++ {
+			x, y, size := bookSlot(index-start, opts.AnswersPerPage)
+			if puzzles[index].Solution != nil {
+				drawPDFGrid(page, puzzles[index].Solution, x, y, size)
+			}
+			page.TextCentered(x+size/2, y+size+size/18+10, math.Min(12, size/14), false, 0, fmt.Sprint(index+1))
+		}
+	}
+	for index, page := range doc.Pages() {
+		page.TextCentered(page.Width/2, page.Height-bookFooter/2, 9, false, 0.4, fmt.Sprint(index+1))
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// bookPage adds a page to the document, with the heading at the top.
+func bookPage(doc *pdf.Document, heading string) *pdf.Page {
+	page := doc.AddPage(pdf.A4Width, pdf.A4Height)
+	if heading != "" {
+		page.TextCentered(page.Width/2, bookMargin+bookHeader/2, 18, true, 0, heading)
+	}
+	return page
+}
+
+// bookSlot returns the top-left corner and the size of the grid at the index on a page with perPage grids.
+// The grids are laid out in the columns and rows which give the largest grids.
+func bookSlot(index int, perPage int) (x float64, y float64, size float64) {
+	width := pdf.A4Width - 2*bookMargin
+	height := pdf.A4Height - 2*bookMargin - bookHeader - bookFooter
+	columns := 1
+	for c := 1; c <= perPage; c++ {
+		if slotSize(width, height, c, perPage) > slotSize(width, height, columns, perPage) {
+			columns = c
+		}
+	}
+	rows := (perPage + columns - 1) / columns
+	size = slotSize(width, height, columns, perPage)
+	slotWidth, slotHeight := width/float64(columns), height/float64(rows)
+	x = bookMargin + float64(index%columns)*slotWidth + (slotWidth-size)/2
+	y = bookMargin + bookHeader + float64(index/columns)*slotHeight + (slotHeight-size*1.1)/2
+	return
+}
+
+// slotSize returns the size of the grids when laid out in the columns, leaving room for the label under each grid.
+func slotSize(width float64, height float64, columns int, perPage int) float64 {
+	rows := (perPage + columns - 1) / columns
+	return math.Min(width/float64(columns)*0.9, height/float64(rows)/1.1*0.9)
+}
+
+// drawPDFGrid draws the board with the top-left corner at {x, y}. Givens are in bold, and placed values in gray.
+func drawPDFGrid(page *pdf.Page, board *datatypes.Board, x float64, y float64, size float64) {
+	cells, block := gridSize(board)
+	cell := size / float64(cells)
+	fontSize := cell * 0.6
+	for i := 0; i < cells; i++ {
+		for j := 0; j < cells; j++ {
+			baseline := y + float64(i)*cell + cell/2 + fontSize*0.36
+			center := x + float64(j)*cell + cell/2
+			if board.Givens[i][j] > 0 {
+				page.TextCentered(center, baseline, fontSize, true, 0, fmt.Sprint(board.Givens[i][j]))
+			} else if board.Placed[i][j] > 0 {
+				page.TextCentered(center, baseline, fontSize, false, 0.35, fmt.Sprint(board.Placed[i][j]))
+			}
+		}
+	}
+	for k := 0; k <= cells; k++ {
+		width := math.Max(0.4, size/600)
+		if k%block == 0 {
+			width *= 3
+		}
+		offset := float64(k) * cell
+		page.Line(x, y+offset, x+size, y+offset, width, 0)
+		page.Line(x+offset, y, x+offset, y+size, width, 0)
+	}
+}
@@ -0,0 +1,119 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// Animation builds an animated GIF of a board, frame by frame.
+// Each frame only holds the cells which changed since the previous frame, so that long animations stay small.
+type Animation struct {
+	opts        ImageOptions
+	delay       int
+	geometry    imageGeometry
+	canvas      *image.RGBA
+	previous    *datatypes.Board
+	highlighted map[datatypes.Position]color.Color
+	colors      map[color.RGBA]uint8
+	gif         gif.GIF
+}
+
+// NewAnimation creates an Animation where each frame is shown for the delay, in 1/100 of a second.
+// The Highlights of the options are ignored, as they are given for each frame.
+func NewAnimation(opts ImageOptions, delay int) *Animation {
+	opts.Highlights = nil
+	return &Animation{opts: opts, delay: delay, colors: make(map[color.RGBA]uint8)}
+}
+
+// Add adds a frame showing the board, with the highlighted cells.
+// The board is copied, so it can be changed after the call.
+func (a *Animation) Add(board *datatypes.Board, highlights map[datatypes.Position]color.Color) {
+	opts := a.opts
+	opts.Highlights = highlights
+	if a.canvas == nil {
+		a.canvas = Image(board, opts)
+		a.geometry = newImageGeometry(board, opts)
+		a.addFrame(a.canvas.Bounds())
+	} else {
+		dirty := image.Rectangle{}
+		g := a.geometry
+		for i := 0; i < g.size; i++ {
+			for j := 0; j < g.size; j++ {
+				pos := datatypes.Position{X: i, Y: j}
+				if a.highlighted[pos] != highlights[pos] || cellChanged(a.previous, board, i, j) {
+					drawCell(a.canvas, board, i, j, g, opts)
+					dirty = dirty.Union(g.cellRect(i, j).Inset(-g.thick))
+				}
+			}
+		}
+		if dirty.Empty() {
+			a.gif.Delay[len(a.gif.Delay)-1] += a.delay
+		} else {
+			dirty = dirty.Intersect(a.canvas.Bounds())
+			drawLines(a.canvas, g, dirty)
+			a.addFrame(dirty)
+		}
+	}
+	a.previous = board.Copy()
+	a.highlighted = highlights
+}
+
+// addFrame adds the part of the canvas inside the rectangle as a frame.
+// Colors are mapped to the nearest color of the palette, and the mapping is cached as the images use few colors.
+func (a *Animation) addFrame(rect image.Rectangle) {
+	frame := image.NewPaletted(rect, palette.Plan9)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := a.canvas.RGBAAt(x, y)
+			index, ok := a.colors[c]
+			if !ok {
+				index = uint8(frame.Palette.Index(c))
+				a.colors[c] = index
+			}
+			frame.SetColorIndex(x, y, index)
+		}
+	}
+	a.gif.Image = append(a.gif.Image, frame)
+	a.gif.Delay = append(a.gif.Delay, a.delay)
+	a.gif.Disposal = append(a.gif.Disposal, gif.DisposalNone)
+}
+
+// Frames returns the number of frames added.
+func (a *Animation) Frames() int {
+	return len(a.gif.Image)
+}
+
+// Encode writes the animation as a GIF which plays once, and stays on the last frame for 3 seconds.
+func (a *Animation) Encode(w io.Writer) error {
+	if len(a.gif.Image) > 0 {
+		a.gif.Delay[len(a.gif.Delay)-1] += 300
+	}
+	a.gif.LoopCount = -1
+	a.gif.Config = image.Config{ColorModel: color.Palette(palette.Plan9), Width: a.geometry.total, Height: a.geometry.total}
+	return gif.EncodeAll(w, &a.gif)
+}
+
+// cellChanged returns true if the cell at {i, j} is drawn differently on the two boards.
+func cellChanged(previous *datatypes.Board, board *datatypes.Board, i int, j int) bool {
+	if previous.Givens[i][j] != board.Givens[i][j] || previous.Placed[i][j] != board.Placed[i][j] {
+		return true
+	}
+	before, after := previous.Candidates[i][j], board.Candidates[i][j]
+	if len(before) != len(after) {
+		return true
+	}
+	for val := range before {
+		if !after[val] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// TestAnimation verifies that a frame only holds the changed cells, and that the animation is decoded as a GIF.
+func TestAnimation(t *testing.T) {
+	board := datatypes.NewBoard()
+	animation := NewAnimation(ImageOptions{CellSize: 20}, 10)
+	animation.Add(board, nil)
+	board.Placed[4][4] = 5
+	animation.Add(board, map[datatypes.Position]color.Color{{X: 4, Y: 4}: color.RGBA{0, 0xff, 0, 0xff}})
+	animation.Add(board, map[datatypes.Position]color.Color{{X: 4, Y: 4}: color.RGBA{0, 0xff, 0, 0xff}})
+	if animation.Frames() != 2 {
+		t.Error("Expected 2 frames, got ", animation.Frames())
+	}
+	var out bytes.Buffer
+	if err := animation.Encode(&out); err != nil {
+		t.Error("Expected no error, got ", err)
+	}
+	decoded, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatal("Expected a GIF, got ", err)
+	}
+	if width := decoded.Image[1].Bounds().Dx(); width >= 186 {
+		t.Error("Expected the second frame to hold one cell, got width ", width)
+	}
+	if decoded.Delay[1] != 320 {
+		t.Error("Expected a delay of 320 for the last frame, got ", decoded.Delay[1])
+	}
+}
@@ -0,0 +1,109 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// point is a point of a glyph, in a unit square with {0, 0} at the top-left corner.
+type point struct {
+	x float64
+	y float64
+}
+
+// arc returns the points of a circular arc from the angle start to the angle end, in degrees counterclockwise from the right.
+func arc(cx float64, cy float64, r float64, start float64, end float64) []point {
+	steps := int(math.Abs(end-start)/10) + 1
+	var points []point
+	for k := 0; k <= steps; k++ {
+		angle := (start + (end-start)*float64(k)/float64(steps)) * math.Pi / 180
+		points = append(points, point{cx + r*math.Cos(angle), cy - r*math.Sin(angle)})
+	}
+	return points
+}
+
+// join returns the points of the parts in a single stroke.
+func join(parts ...[]point) []point {
+	var points []point
+	for _, part := range parts {
+		points = append(points, part...)
+	}
+	return points
+}
+
+// digitStrokes are the strokes of the built-in digits from 1 to 9. Each stroke is a line through its points.
+var digitStrokes = [10][][]point{
+	1: {{{0.25, 0.2}, {0.55, 0}, {0.55, 1}}, {{0.25, 1}, {0.85, 1}}},
+	2: {join(arc(0.5, 0.28, 0.28, 170, -40), []point{{0.15, 1}, {0.85, 1}})},
+	3: {arc(0.5, 0.26, 0.24, 160, -90), arc(0.5, 0.74, 0.26, 90, -160)},
+	4: {{{0.65, 1}, {0.65, 0}, {0.1, 0.7}, {0.9, 0.7}}},
+	5: {join([]point{{0.78, 0}, {0.27, 0}, {0.22, 0.46}}, arc(0.5, 0.69, 0.31, 140, -150))},
+	6: {arc(0.5, 0.69, 0.29, 0, 360), arc(0.79, 0.69, 0.58, 94, 180)},
+	7: {{{0.15, 0}, {0.85, 0}, {0.4, 1}}},
+	8: {arc(0.5, 0.245, 0.225, 0, 360), arc(0.5, 0.72, 0.265, 0, 360)},
+	9: {arc(0.5, 0.31, 0.29, 0, 360), arc(0.21, 0.31, 0.58, 0, -86)},
+}
+
+// glyphAspect is the width of a digit, relative to its height.
+const glyphAspect = 0.62
+
+// DrawDigit draws the built-in glyph of the digit, centered in the rectangle, with a height of size pixels.
+// weight is the width of the strokes relative to the size, e.g. 0.1 for regular, and 0.15 for bold digits.
+// The edges of the strokes are anti-aliased.
+func DrawDigit(img draw.Image, val int, rect image.Rectangle, size float64, weight float64, c color.Color) {
+	if val < 1 || val > 9 {
+		return
+	}
+	half := size * weight / 2
+	width := size * glyphAspect
+	left := float64(rect.Min.X+rect.Max.X)/2 - width/2
+	top := float64(rect.Min.Y+rect.Max.Y)/2 - size/2
+	var strokes [][]point
+	for _, stroke := range digitStrokes[val] {
+		var scaled []point
+		for _, p := range stroke {
+			scaled = append(scaled, point{left + p.x*width, top + p.y*size})
+		}
+		strokes = append(strokes, scaled)
+	}
+	bounds := image.Rect(int(left-half-1), int(top-half-1), int(left+width+half+2), int(top+size+half+2)).Intersect(rect)
+	r, g, b, _ := c.RGBA()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			distance := math.Inf(1)
+			for _, stroke := range strokes {
+				for k := 1; k < len(stroke); k++ {
+					distance = math.Min(distance, segmentDistance(float64(x)+0.5, float64(y)+0.5, stroke[k-1], stroke[k]))
+				}
+			}
+			alpha := math.Max(0, math.Min(1, half+0.5-distance))
+			if alpha > 0 {
+				blend(img, x, y, r, g, b, alpha)
+			}
+		}
+	}
+}
+
+// segmentDistance returns the distance from {x, y} to the segment from a to b.
+func segmentDistance(x float64, y float64, a point, b point) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((x-a.x)*dx+(y-a.y)*dy)/length))
+	}
+	return math.Hypot(x-a.x-t*dx, y-a.y-t*dy)
+}
+
+// blend mixes the color, given as 16 bit components, into the pixel at {x, y} with the alpha from 0 to 1.
+func blend(img draw.Image, x int, y int, r uint32, g uint32, b uint32, alpha float64) {
+	pr, pg, pb, _ := img.At(x, y).RGBA()
+	mix := func(c uint32, p uint32) uint8 {
+		return uint8((float64(c)*alpha + float64(p)*(1-alpha)) / 257)
+	}
+	img.Set(x, y, color.RGBA{mix(r, pr), mix(g, pg), mix(b, pb), 255})
+}
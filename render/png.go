@@ -0,0 +1,119 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// Colors of the image output, matching the SVG output.
+var (
+	imageBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	imageLine       = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	imageGiven      = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	imagePlaced     = color.RGBA{0x1a, 0x5f, 0xb4, 0xff}
+	imageCandidate  = color.RGBA{0x70, 0x70, 0x70, 0xff}
+)
+
+// ImageOptions controls how a board is drawn as an image.
+// CellSize is the width of a cell in pixels, and 48 is used if it is 0.
+// Candidates shows the candidates of the empty cells as pencil marks.
+// Highlights gives the fill color of the highlighted cells.
+type ImageOptions struct {
+	CellSize   int
+	Candidates bool
+	Highlights map[datatypes.Position]color.Color
+}
+
+// imageGeometry gives the positions of the cells and lines of an image.
+type imageGeometry struct {
+	size   int
+	block  int
+	cell   int
+	thin   int
+	thick  int
+	margin int
+	total  int
+}
+
+// newImageGeometry returns the geometry of the image of the board, for the cell size of the options.
+func newImageGeometry(board *datatypes.Board, opts ImageOptions) imageGeometry {
+	g := imageGeometry{cell: opts.CellSize}
+	if g.cell == 0 {
+		g.cell = 48
+	}
+	g.size, g.block = gridSize(board)
+	g.thin, g.thick = svgLineWidths(g.cell)
+	g.margin = g.thick
+	g.total = g.cell*g.size + 2*g.margin
+	return g
+}
+
+// cellRect returns the rectangle of the cell at {i, j}.
+func (g imageGeometry) cellRect(i int, j int) image.Rectangle {
+	return image.Rect(g.margin+j*g.cell, g.margin+i*g.cell, g.margin+(j+1)*g.cell, g.margin+(i+1)*g.cell)
+}
+
+// Image draws the board, with thick lines around the blocks. Givens are drawn in bold black, and placed values in blue.
+func Image(board *datatypes.Board, opts ImageOptions) *image.RGBA {
+	g := newImageGeometry(board, opts)
+	img := image.NewRGBA(image.Rect(0, 0, g.total, g.total))
+	draw.Draw(img, img.Bounds(), image.NewUniform(imageBackground), image.Point{}, draw.Src)
+	for i := 0; i < g.size; i++ {
+		for j := 0; j < g.size; j++ {
+			drawCell(img, board, i, j, g, opts)
+		}
+	}
+	drawLines(img, g, img.Bounds())
+	return img
+}
+
+// drawCell draws the background and the value, or the candidates, of the cell at {i, j}.
+func drawCell(img draw.Image, board *datatypes.Board, i int, j int, g imageGeometry, opts ImageOptions) {
+	rect := g.cellRect(i, j)
+	background := color.Color(imageBackground)
+	if c, ok := opts.Highlights[datatypes.Position{X: i, Y: j}]; ok {
+		background = c
+	}
+	draw.Draw(img, rect, image.NewUniform(background), image.Point{}, draw.Src)
+	digitSize := float64(g.cell) * 0.6
+	switch {
+	case board.Givens[i][j] > 0:
+		DrawDigit(img, board.Givens[i][j], rect, digitSize, 0.15, imageGiven)
+	case board.Placed[i][j] > 0:
+		DrawDigit(img, board.Placed[i][j], rect, digitSize, 0.1, imagePlaced)
+	case opts.Candidates:
+		half := g.cell / (2 * g.block)
+		for _, val := range datatypes.SortedValues(board.Candidates[i][j]) {
+			cx, cy := candidateCenter(rect.Min.X, rect.Min.Y, g.cell, g.block, val)
+			DrawDigit(img, val, image.Rect(cx-half, cy-half, cx+half, cy+half), float64(g.cell)/(float64(g.block)+1), 0.1, imageCandidate)
+		}
+	}
+}
+
+// drawLines draws the part of the lines between the cells which is inside the clip rectangle.
+func drawLines(img draw.Image, g imageGeometry, clip image.Rectangle) {
+	for k := 0; k <= g.size; k++ {
+		width := g.thin
+		if k%g.block == 0 {
+			width = g.thick
+		}
+		pos := g.margin + k*g.cell - width/2
+		start, end := g.margin-g.thick/2, g.margin+g.size*g.cell+g.thick-g.thick/2
+		line := image.NewUniform(imageLine)
+		draw.Draw(img, image.Rect(start, pos, end, pos+width).Intersect(clip), line, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(pos, start, pos+width, end).Intersect(clip), line, image.Point{}, draw.Src)
+	}
+}
+
+// PNG draws the board as a PNG image.
+func PNG(w io.Writer, board *datatypes.Board, opts ImageOptions) error {
+	return png.Encode(w, Image(board, opts))
+}
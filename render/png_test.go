@@ -0,0 +1,38 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// TestImage verifies the size of the image, the block lines, the highlights and the digits.
+func TestImage(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 1
+	img := Image(board, ImageOptions{CellSize: 20, Highlights: map[datatypes.Position]color.Color{{X: 8, Y: 8}: color.RGBA{0xff, 0, 0, 0xff}}})
+	if img.Bounds().Dx() != 186 {
+		t.Error("Expected width 186, got ", img.Bounds().Dx())
+	}
+	if c := img.RGBAAt(63, 100); c != imageLine {
+		t.Error("Expected a block line at x=63, got ", c)
+	}
+	if c := img.RGBAAt(175, 175); c.R != 0xff || c.G != 0 {
+		t.Error("Expected the highlight in the last cell, got ", c)
+	}
+	dark := 0
+	for y := 6; y < 21; y++ {
+		for x := 6; x < 21; x++ {
+			if c := img.RGBAAt(x, y); c.R < 0x80 {
+				dark++
+			}
+		}
+	}
+	if dark < 10 || dark > 100 {
+		t.Error("Expected the strokes of 1 in the first cell, got dark pixels: ", dark)
+	}
+}
@@ -0,0 +1,144 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// Report is what the solver did for a puzzle. HTMLReport writes it as a single HTML page.
+// Candidates is the board after the elimination, before any guess. Stats are shown in their order.
+// MoreSteps and MoreSolutions are the numbers of steps and solutions left out of the report.
+// Conflict is set if the elimination finds a conflict in the puzzle, before any guess.
+type Report struct {
+	Title         string
+	Puzzle        *datatypes.Board
+	Conflict      bool
+	Candidates    *datatypes.Board
+	Steps         []SearchStep
+	MoreSteps     int
+	Solutions     []*datatypes.Board
+	MoreSolutions int
+	Stats         []Stat
+}
+
+// SearchStep is a step of the search: Kind is "guess" or "backtrack" for the value at the position,
+// or "solution" where Val is the number of the solution.
+// Depth is the number of guesses in effect after the step, and Conflict is set for a backtrack when the guess
+// led to a conflict without any further guess.
+type SearchStep struct {
+	Kind     string
+	Pos      datatypes.Position
+	Val      int
+	Depth    int
+	Conflict bool
+}
+
+// Stat is a named value shown in the statistics of a report.
+type Stat struct {
+	Name  string
+	Value string
+}
+
+// Text returns the description of the step, with cells named by row and column, like r3c5.
+func (step SearchStep) Text() string {
+	cell := fmt.Sprintf("r%dc%d", step.Pos.X+1, step.Pos.Y+1)
+	switch step.Kind {
+	case "guess":
+		return fmt.Sprintf("Guess %s = %d", cell, step.Val)
+	case "backtrack":
+		if step.Conflict {
+			return fmt.Sprintf("Backtrack from %s = %d (conflict)", cell, step.Val)
+		}
+		return fmt.Sprintf("Backtrack from %s = %d", cell, step.Val)
+	}
+	return fmt.Sprintf("Solution %d found", step.Val)
+}
+
+// HTMLReport writes the report as an HTML page which works offline: the grids are inline SVG, and the styles are inline.
+func HTMLReport(w io.Writer, report Report) error {
+	svg := func(board *datatypes.Board, candidates bool, cellSize int) (template.HTML, error) {
+		var out strings.Builder
+		err := SVG(&out, board, SVGOptions{CellSize: cellSize, Candidates: candidates})
+		return template.HTML(out.String()), err
+	}
+	var err error
+	page := struct {
+		Report
+		Puzzle, Candidates template.HTML
+		Solutions          []template.HTML
+	}{Report: report}
+	if page.Puzzle, err = svg(report.Puzzle, false, 40); err != nil {
+		return err
+	}
+	if report.Candidates != nil {
+		if page.Candidates, err = svg(report.Candidates, true, 40); err != nil {
+			return err
+		}
+	}
+	for _, solution := range report.Solutions {
+		image, err := svg(solution, false, 24)
+		if err != nil {
+			return err
+		}
+		page.Solutions = append(page.Solutions, image)
+	}
+	return reportTemplate.Execute(w, page)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"indent": func(depth int) template.CSS {
+		return template.CSS(fmt.Sprintf("padding-left: %.1fem", 1.5*float64(depth)))
+	},
+	"inc": func(index int) int { return index + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 2em; color: #202020; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #d0d0d0; }
+table.stats td { padding: 0.2em 1.5em 0.2em 0; }
+.grids { display: flex; flex-wrap: wrap; gap: 2em; }
+.grids figure { margin: 0; }
+.grids figcaption { text-align: center; margin-top: 0.5em; color: #606060; }
+ol.steps { font-family: Menlo, Consolas, monospace; font-size: 0.9em; }
+.guess { color: #8a6d00; }
+.backtrack { color: #b01c1c; }
+.solution { color: #1a7f37; font-weight: bold; }
+.more { color: #606060; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="stats">
+{{range .Stats}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+<div class="grids">
+<figure>{{.Puzzle}}<figcaption>Puzzle</figcaption></figure>
+{{if .Candidates}}<figure>{{.Candidates}}<figcaption>Possible values after elimination</figcaption></figure>{{end}}
+</div>
+<h2>Search</h2>
+{{if .Conflict}}<p>The elimination finds a conflict in the puzzle, before any guess.</p>
+{{else if .Steps}}<ol class="steps">
+{{range .Steps}}<li class="{{.Kind}}" style="{{indent .Depth}}">{{.Text}}</li>
+{{end}}</ol>
+{{else}}<p>Solved without any guess.</p>
+{{end}}{{if .MoreSteps}}<p class="more">{{.MoreSteps}} more steps are left out.</p>
+{{end}}<h2>Solutions</h2>
+{{if .Solutions}}<div class="grids">
+{{range $index, $solution := .Solutions}}<figure>{{$solution}}<figcaption>Solution {{inc $index}}</figcaption></figure>
+{{end}}</div>
+{{else}}<p>No solution.</p>
+{{end}}{{if .MoreSolutions}}<p class="more">{{.MoreSolutions}} more solutions are left out.</p>
+{{end}}</body>
+</html>
+`))
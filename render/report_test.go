@@ -0,0 +1,57 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// TestHTMLReport verifies that the grids are inlined, and that the steps and the statistics are escaped.
+func TestHTMLReport(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 5
+	report := Report{
+		Title:     "Puzzle <1>",
+		Puzzle:    board,
+		Steps:     []SearchStep{{Kind: "guess", Pos: datatypes.Position{X: 2, Y: 4}, Val: 7, Depth: 1}, {Kind: "backtrack", Pos: datatypes.Position{X: 2, Y: 4}, Val: 7, Conflict: true}},
+		Solutions: []*datatypes.Board{board, board},
+		Stats:     []Stat{{Name: "Guesses", Value: "1"}},
+	}
+	var out bytes.Buffer
+	if err := HTMLReport(&out, report); err != nil {
+		t.Error("Expected no error, got ", err)
+	}
+	page := out.String()
+	expected := []string{
+		"<title>Puzzle &lt;1&gt;</title>",
+		`<li class="guess" style="padding-left: 1.5em">Guess r3c5 = 7</li>`,
+		"Backtrack from r3c5 = 7 (conflict)",
+		"<tr><td>Guesses</td><td>1</td></tr>",
+		"Solution 2</figcaption>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(page, e) {
+			t.Error("Expected ", e, " in\n", page)
+		}
+	}
+	if strings.Count(page, "<svg") != 3 || strings.Contains(page, "src=") {
+		t.Error("Expected 3 inline grids, got ", strings.Count(page, "<svg"))
+	}
+}
+
+// TestHTMLReportConflict verifies that a report of a puzzle with a conflict tells it, and that there is no solution.
+func TestHTMLReportConflict(t *testing.T) {
+	var out bytes.Buffer
+	if err := HTMLReport(&out, Report{Puzzle: datatypes.NewBoard(), Conflict: true}); err != nil {
+		t.Error("Expected no error, got ", err)
+	}
+	page := out.String()
+	if !strings.Contains(page, "finds a conflict in the puzzle") || !strings.Contains(page, "<p>No solution.</p>") {
+		t.Error("Expected a conflict and no solution in\n", page)
+	}
+}
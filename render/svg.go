@@ -0,0 +1,117 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// Colors of the SVG and image output.
+const (
+	colorBackground = "#ffffff"
+	colorLine       = "#000000"
+	colorGiven      = "#000000"
+	colorPlaced     = "#1a5fb4"
+	colorCandidate  = "#707070"
+)
+
+// SVGOptions controls how a board is drawn as SVG.
+// CellSize is the width of a cell in pixels, and 48 is used if it is 0.
+// Candidates shows the candidates of the empty cells as pencil marks.
+// Highlights gives the fill color of the highlighted cells, as an SVG color like "#ffe08a" or "yellow".
+type SVGOptions struct {
+	CellSize   int
+	Candidates bool
+	Highlights map[datatypes.Position]string
+}
+
+// SVG draws the board as an SVG document, with thick lines around the blocks.
+// Givens are drawn in bold black, and placed values in blue.
+func SVG(w io.Writer, board *datatypes.Board, opts SVGOptions) error {
+	size, block := gridSize(board)
+	cell := opts.CellSize
+	if cell == 0 {
+		cell = 48
+	}
+	thin, thick := svgLineWidths(cell)
+	margin := thick
+	total := cell*size + 2*margin
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", total, total, total, total)
+	if board.Info.Title != "" {
+		fmt.Fprintf(&out, "<title>%s</title>\n", html.EscapeString(board.Info.Title))
+	}
+	fmt.Fprintf(&out, `<rect width="%d" height="%d" fill="%s"/>`+"\n", total, total, colorBackground)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			if color, ok := opts.Highlights[datatypes.Position{X: i, Y: j}]; ok {
+				fmt.Fprintf(&out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", margin+j*cell, margin+i*cell, cell, cell, html.EscapeString(color))
+			}
+		}
+	}
+	for k := 0; k <= size; k++ {
+		width := thin
+		if k%block == 0 {
+			width = thick
+		}
+		pos := margin + k*cell
+		fmt.Fprintf(&out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n", margin, pos, margin+size*cell, pos, colorLine, width)
+		fmt.Fprintf(&out, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n", pos, margin, pos, margin+size*cell, colorLine, width)
+	}
+	out.WriteString(`<g font-family="Helvetica, Arial, sans-serif" text-anchor="middle" dominant-baseline="central">` + "\n")
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			x, y := margin+j*cell, margin+i*cell
+			switch {
+			case board.Givens[i][j] > 0:
+				svgText(&out, x+cell/2, y+cell/2, cell*6/10, colorGiven, "bold", board.Givens[i][j])
+			case board.Placed[i][j] > 0:
+				svgText(&out, x+cell/2, y+cell/2, cell*6/10, colorPlaced, "normal", board.Placed[i][j])
+			case opts.Candidates:
+				for _, val := range datatypes.SortedValues(board.Candidates[i][j]) {
+					cx, cy := candidateCenter(x, y, cell, block, val)
+					svgText(&out, cx, cy, cell/(block+1), colorCandidate, "normal", val)
+				}
+			}
+		}
+	}
+	out.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// svgText writes a value centered at {x, y}.
+func svgText(out *strings.Builder, x int, y int, fontSize int, color string, weight string, val int) {
+	fmt.Fprintf(out, `<text x="%d" y="%d" font-size="%d" font-weight="%s" fill="%s">%d</text>`+"\n", x, y, fontSize, weight, color, val)
+}
+
+// svgLineWidths returns the width of the lines between the cells, and of the lines around the blocks.
+func svgLineWidths(cell int) (int, int) {
+	thin := cell / 48
+	if thin < 1 {
+		thin = 1
+	}
+	return thin, 3 * thin
+}
+
+// gridSize returns the number of cells in a row of the board, and in a row of a block.
+func gridSize(board *datatypes.Board) (size int, block int) {
+	size, block = len(board.Givens), 1
+	for block*block < size {
+		block++
+	}
+	return
+}
+
+// candidateCenter returns the center of the pencil mark of val, in the cell with top-left corner at {x, y}.
+// The pencil marks are laid out like the cells of a block.
+func candidateCenter(x int, y int, cell int, block int, val int) (int, int) {
+	row, column := (val-1)/block, (val-1)%block
+	return x + (2*column+1)*cell/(2*block), y + (2*row+1)*cell/(2*block)
+}
@@ -0,0 +1,36 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// TestSVG verifies the highlights, block lines and styles of the SVG output.
+func TestSVG(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 5
+	board.Placed[0][1] = 3
+	board.Candidates[0][2] = map[int]bool{9: true}
+	var out bytes.Buffer
+	SVG(&out, board, SVGOptions{Candidates: true, Highlights: map[datatypes.Position]string{{X: 1, Y: 1}: "yellow"}})
+	svg := out.String()
+	expected := []string{
+		`width="438" height="438"`,
+		`<rect x="51" y="51" width="48" height="48" fill="yellow"/>`,
+		`stroke-width="3"`,
+		`font-weight="bold" fill="#000000">5</text>`,
+		`font-weight="normal" fill="#1a5fb4">3</text>`,
+		`<text x="139" y="43" font-size="12" font-weight="normal" fill="#707070">9</text>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(svg, e) {
+			t.Error("Expected ", e, " in\n", svg)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+// Package render draws sudoku boards for terminals, documents and images.
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+const max int = 9
+
+// ANSI escape sequences for the styles of the terminal output.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiGiven     = "\x1b[1m"
+	ansiPlaced    = "\x1b[36m"
+	ansiCandidate = "\x1b[2m"
+)
+
+// TextOptions controls how a board is drawn as text.
+// Color uses ANSI colors to tell givens from placed values, otherwise placed values are prefixed by '+'.
+// Candidates shows the candidates of the empty cells, as a 3x3 mini-grid in each cell.
+// Highlights gives the ANSI style of the highlighted cells, like "\x1b[41m" for a red background. It is used only with Color.
+// Symbols are the characters drawn for the values 1 to 9, or the digits if it is empty.
+type TextOptions struct {
+	Color      bool
+	Candidates bool
+	Highlights map[datatypes.Position]string
+	Symbols    string
+}
+
+// DefaultTextOptions returns the options for drawing to w: colors are used only if w is a terminal.
+func DefaultTextOptions(w io.Writer) TextOptions {
+	return TextOptions{Color: IsTerminal(w) && os.Getenv("NO_COLOR") == ""}
+}
+
+// IsTerminal returns true if w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Text draws the board with Unicode box-drawing borders.
+// Empty cells without candidates, or all the empty cells if candidates are not shown, are left blank.
+func Text(w io.Writer, board *datatypes.Board, opts TextOptions) error {
+	width, height := textCellWidth(opts), 1
+	if opts.Candidates {
+		height = 3
+	}
+	var out strings.Builder
+	out.WriteString(textBorder(width, "╔", "╤", "╦", "╗", "═") + "\n")
+	for i := 0; i < max; i++ {
+		if i > 0 && i%3 == 0 {
+			out.WriteString(textBorder(width, "╠", "╪", "╬", "╣", "═") + "\n")
+		} else if i > 0 {
+			out.WriteString(textBorder(width, "╟", "┼", "╫", "╢", "─") + "\n")
+		}
+		for line := 0; line < height; line++ {
+			for j := 0; j < max; j++ {
+				if j%3 == 0 {
+					out.WriteString("║")
+				} else {
+					out.WriteString("│")
+				}
+				cell := textCell(board, i, j, line, opts)
+				if highlight, ok := opts.Highlights[datatypes.Position{X: i, Y: j}]; ok && opts.Color {
+					cell = highlight + strings.ReplaceAll(cell, ansiReset, ansiReset+highlight) + ansiReset
+				}
+				out.WriteString(cell)
+			}
+			out.WriteString("║\n")
+		}
+	}
+	out.WriteString(textBorder(width, "╚", "╧", "╩", "╝", "═") + "\n")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// textBorder returns a horizontal border for cells of the given width.
+func textBorder(width int, left string, cell string, block string, right string, line string) string {
+	border := left
+	for j := 0; j < max; j++ {
+		if j > 0 && j%3 == 0 {
+			border += block
+		} else if j > 0 {
+			border += cell
+		}
+		border += strings.Repeat(line, width)
+	}
+	return border + right
+}
+
+// textCell returns the given line of the cell at {i, j}, padded to the width of the cell.
+// The value of the cell is shown in the middle line when candidates are shown.
+func textCell(board *datatypes.Board, i int, j int, line int, opts TextOptions) string {
+	given := board.Givens[i][j]
+	placed := board.Placed[i][j]
+	if given == 0 && placed == 0 {
+		if !opts.Candidates || board.Candidates[i][j] == nil {
+			return strings.Repeat(" ", textCellWidth(opts))
+		}
+		return " " + style(candidateLine(board.Candidates[i][j], line, opts), ansiCandidate, opts.Color) + " "
+	}
+	pad := ""
+	if opts.Candidates {
+		pad = " "
+		if line != 1 {
+			return strings.Repeat(" ", textCellWidth(opts))
+		}
+	}
+	if given > 0 {
+		return pad + " " + style(symbol(given, opts), ansiGiven, opts.Color) + " " + pad
+	}
+	if opts.Color {
+		return pad + " " + style(symbol(placed, opts), ansiPlaced, true) + " " + pad
+	}
+	return pad + "+" + symbol(placed, opts) + " " + pad
+}
+
+// textCellWidth returns the number of characters in a line of a cell.
+func textCellWidth(opts TextOptions) int {
+	if opts.Candidates {
+		return 5
+	}
+	return 3
+}
+
+// candidateLine returns the candidates for one line of the 3x3 mini-grid of a cell, with a space for a missing candidate.
+func candidateLine(candidates map[int]bool, line int, opts TextOptions) string {
+	s := ""
+	for val := line*3 + 1; val <= line*3+3; val++ {
+		if candidates[val] {
+			s += symbol(val, opts)
+		} else {
+			s += " "
+		}
+	}
+	return s
+}
+
+// symbol returns the character drawn for the value.
+func symbol(val int, opts TextOptions) string {
+	if symbols := []rune(opts.Symbols); val >= 1 && val <= len(symbols) {
+		return string(symbols[val-1])
+	}
+	return fmt.Sprint(val)
+}
+
+// style wraps the text in the ANSI style, if colors are used.
+func style(text string, ansi string, color bool) string {
+	if !color {
+		return text
+	}
+	return ansi + text + ansiReset
+}
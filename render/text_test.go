@@ -0,0 +1,38 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// TestText verifies the borders, the plain text styles of givens, placed values and candidates, and the highlights.
+func TestText(t *testing.T) {
+	board := datatypes.NewBoard()
+	board.Givens[0][0] = 5
+	board.Placed[0][1] = 3
+	board.Candidates[0][2] = map[int]bool{1: true, 6: true}
+	var out bytes.Buffer
+	Text(&out, board, TextOptions{})
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 20 || lines[1] != "║ 5 │+3 │   ║   │   │   ║   │   │   ║" {
+		t.Error("Expected 19 lines with 5 and +3, got\n", out.String())
+	}
+	out.Reset()
+	Text(&out, board, TextOptions{Candidates: true, Color: true})
+	lines = strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[2], "║  \x1b[1m5\x1b[0m  │  \x1b[36m3\x1b[0m  │ \x1b[2m  6\x1b[0m ║") {
+		t.Errorf("Expected colored 5, 3 and candidate 6, got %q", lines[2])
+	}
+	out.Reset()
+	Text(&out, board, TextOptions{Color: true, Highlights: map[datatypes.Position]string{{X: 0, Y: 1}: "\x1b[7m"}})
+	lines = strings.Split(out.String(), "\n")
+	if !strings.Contains(lines[1], "│\x1b[7m \x1b[36m3\x1b[0m\x1b[7m \x1b[0m│") {
+		t.Errorf("Expected highlighted 3, got %q", lines[1])
+	}
+}
@@ -0,0 +1,111 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+const solutionStep = "solution"
+
+// maxReportSteps and maxReportSolutions limit the size of the report for puzzles with a long search, or many solutions.
+const maxReportSteps, maxReportSolutions = 5000, 100
+
+// solveReport collects the steps of the solver for the html output.
+// depth is the number of guesses in effect, and start is when the solver started.
+type solveReport struct {
+	mutex        sync.Mutex
+	report       render.Report
+	depth        int
+	maxDepth     int
+	guesses      int
+	backtracks   int
+	placements   int
+	eliminations int
+	solutions    int
+	start        time.Time
+}
+
+// newSolveReport creates a solveReport for the puzzle.
+func newSolveReport(puzzle *datatypes.Board) *solveReport {
+	return &solveReport{report: render.Report{Title: "Sudoku solve report", Puzzle: puzzle}, start: time.Now()}
+}
+
+// record counts the step, and adds the guesses and backtracks to the search steps of the report.
+func (r *solveReport) record(kind string, pos datatypes.Position, val int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	switch kind {
+	case placementStep:
+		r.placements++
+	case eliminationStep:
+		r.eliminations++
+	case guessStep:
+		r.guesses++
+		r.depth++
+		if r.depth > r.maxDepth {
+			r.maxDepth = r.depth
+		}
+		r.addStep(render.SearchStep{Kind: kind, Pos: pos, Val: val, Depth: r.depth})
+	case backtrackStep:
+		r.backtracks++
+		r.depth--
+		step := render.SearchStep{Kind: kind, Pos: pos, Val: val, Depth: r.depth}
+		if steps := r.report.Steps; len(steps) > 0 && r.report.MoreSteps == 0 {
+			last := steps[len(steps)-1]
+			step.Conflict = last.Kind == guessStep && last.Pos == pos && last.Val == val
+		}
+		r.addStep(step)
+	}
+}
+
+// addStep adds the step to the report, unless the report already holds maxReportSteps steps.
+func (r *solveReport) addStep(step render.SearchStep) {
+	if len(r.report.Steps) < maxReportSteps {
+		r.report.Steps = append(r.report.Steps, step)
+	} else {
+		r.report.MoreSteps++
+	}
+}
+
+// addSolution adds the solution to the report. It is used as onSolution.
+func (r *solveReport) addSolution(grid *datatypes.Grid, iteration int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.solutions++
+	r.addStep(render.SearchStep{Kind: solutionStep, Val: r.solutions, Depth: r.depth})
+	if len(r.report.Solutions) < maxReportSolutions {
+		r.report.Solutions = append(r.report.Solutions, datatypes.BoardFromGrid(grid, iteration, r.report.Puzzle))
+	} else {
+		r.report.MoreSolutions++
+	}
+}
+
+// finish adds the statistics to the report, for the positions which were not set after the initial elimination.
+// total is the number of solutions as printed by the solver, like 1+ when the search stopped at -max-solutions.
+func (r *solveReport) finish(positions map[datatypes.Position]bool, total string) render.Report {
+	givens, _ := r.report.Puzzle.CountGivens()
+	level := difficulty(positions)
+	if r.report.Conflict {
+		level = "none"
+	}
+	r.report.Stats = []render.Stat{
+		{Name: "Givens", Value: fmt.Sprint(givens)},
+		{Name: "Cells solved before guessing", Value: fmt.Sprint(max*max - givens - len(positions))},
+		{Name: "Values placed by elimination", Value: fmt.Sprint(r.placements)},
+		{Name: "Possible values eliminated", Value: fmt.Sprint(r.eliminations)},
+		{Name: "Guesses", Value: fmt.Sprint(r.guesses)},
+		{Name: "Backtracks", Value: fmt.Sprint(r.backtracks)},
+		{Name: "Maximum guess depth", Value: fmt.Sprint(r.maxDepth)},
+		{Name: "Total solutions", Value: total},
+		{Name: "Difficulty level", Value: level},
+		{Name: "Time", Value: time.Since(r.start).Round(time.Microsecond).String()},
+	}
+	return r.report
+}
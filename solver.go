@@ -5,11 +5,15 @@ package main
 
 import (
 	"fmt"
+	"math/rand"
 	"os"
-	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
 )
 
 const max int = 9
@@ -19,86 +23,190 @@ const rowIdentifier, colIdentifier, blockIdentifier = "r", "c", "b"
 var initIdentifiers = map[string]bool{rowIdentifier: true, colIdentifier: true, blockIdentifier: true}
 var numSolutions int
 
+// onSolution is called by solveByGuessing for each solution. By default the solution is printed.
+// solveByGuessing stops once maxSolutions solutions are found, unless maxSolutions is 0.
+var onSolution = func(grid *datatypes.Grid, iteration int) { printGrid(grid, iteration, false) }
+var maxSolutions int
+
+// searchTimeout limits the time of solveByGuessing, unless it is 0. Once the deadline is passed, timedOut is set,
+// and solveByGuessing returns without trying the other guesses.
+var searchTimeout time.Duration
+var deadline time.Time
+var timedOut bool
+
+// cancelSearch is set from another goroutine to stop solveByGuessing, like a timeout, when its result is no longer needed.
+// It is cleared once the search has stopped.
+var cancelSearch int32
+
+// engine is the engine which solves the puzzles. The backtracking engine eliminates the values of the peers of each value set,
+// and guesses a value when the elimination is stuck. The logic engine applies the techniques of the logic package
+// one step at a time, and stops when they are not enough.
+var engine = backtrackingEngine
+
+const backtrackingEngine, logicEngine = "backtracking", "logic"
+
+// branching is how solveByGuessing chooses the cell to guess: branchMin for the cell with the fewest possible values,
+// branchFirst for the first cell row by row, and branchRandom for a random cell.
+var branching = branchMin
+
+const branchMin, branchFirst, branchRandom = "min", "first", "random"
+
+// recordStep is called, if set, for each change made by the solver: a value placed or eliminated by the elimination,
+// a value guessed, and a guess undone. It can be called from several goroutines at a time during the initial elimination.
+var recordStep func(kind string, pos datatypes.Position, val int)
+
+const placementStep, eliminationStep, guessStep, backtrackStep = "placement", "elimination", "guess", "backtrack"
+
+// printCandidates and candidatesDepth control printing of the possible values of the grid.
+// When printCandidates is set, the grid is printed after the initial elimination, and each time solveByGuessing
+// starts an iteration which is not more than candidatesDepth.
+var printCandidates bool
+var candidatesDepth int
+
 func main() {
-	numSolutions = 0
-	// create the grid.
-	grid := *datatypes.InitGrid()
-	count := 0
-	inputValues := make(map[int]bool)
-	// read input into the grid.
-	for i := 0; i < max; i++ {
-		count += readRow(&grid, i, inputValues)
+	runCommand(os.Args[1:])
+	os.Exit(exitCode)
+}
+
+// solveCommand solves the puzzle from the file given as argument, or from stdin, and prints the solutions,
+// the number of solutions and the difficulty level.
+func solveCommand(args []string) {
+	flags := newFlagSet("solve", "[file]", "Solve a puzzle, and print the solutions with the number of solutions and the difficulty level.\n\n"+exitCodesHelp)
+	in := addInputFlags(flags, "grid")
+	to := flags.String("to", "", "format of the solutions, instead of the printed grids: "+strings.Join(formats.Names(), ", "))
+	flags.BoolVar(&printCandidates, "candidates", false, "print the possible values of each cell after the initial elimination")
+	flags.IntVar(&candidatesDepth, "candidates-depth", 0, "with -candidates, also print the possible values for each guess up to this depth")
+	flags.BoolVar(&prettyPrint, "pretty", false, "draw the grids with box-drawing borders, and colors on a terminal")
+	flags.StringVar(&outputFormat, "output", textOutput, "format of the output: text, svg or png for an image of the first solution, or of the possible values with -candidates, gif for an animation of the solving steps, html for a report of the solving steps and the solutions, or json for the steps of -engine logic")
+	flags.IntVar(&cellSize, "cell-size", 48, "width of a cell in pixels, for the svg, png and gif output")
+	flags.Float64Var(&framesPerSecond, "fps", 10, "frames per second, for the gif output")
+	flags.IntVar(&maxSolutions, "max-solutions", 0, "stop after this number of solutions. All the solutions are found if not given")
+	flags.StringVar(&datatypes.Symbols, "symbols", datatypes.Symbols, "the 9 characters of the values 1 to 9, in the input and the printed grids, like ABCDEFGHI")
+	watch := flags.Bool("watch", false, "watch the puzzle file, and solve it again each time it changes, until interrupted")
+	interval := flags.Duration("interval", 500*time.Millisecond, "with -watch, how often the file is checked for changes")
+	addSearchFlags(flags)
+	addEngineFlag(flags)
+	parseFlags(flags, args)
+	if !outputFormats[outputFormat] {
+		handleInvalidInput("unknown output format "+outputFormat, nil)
+	}
+	if outputFormat == jsonOutput && engine != logicEngine {
+		handleInvalidInput("the json output needs -engine logic", nil)
+	}
+	if err := checkSymbols(datatypes.Symbols); err != nil {
+		handleInvalidInput("-symbols", err)
+	}
+	in.symbols = datatypes.Symbols
+	if *watch {
+		if flags.NArg() != 1 {
+			handleInvalidInput("-watch needs a puzzle file", nil)
+		}
+		watchPuzzle(os.Stdout, flags.Arg(0), in.format(flags.Arg(0)), *interval)
+		return
+	}
+	puzzle = in.readOne(flags.Args())
+	if engine == logicEngine {
+		solveLogically(puzzle)
+		return
+	}
+	if *to != "" {
+		solutionFormat := lookupOutputFormat(*to)
+		onSolution = func(grid *datatypes.Grid, iteration int) {
+			writePuzzles(solutionFormat, []*datatypes.Board{datatypes.BoardFromGrid(grid, iteration, puzzle)})
+		}
 	}
 	// At least 17 values, and 8 distinct values are required for a unique solution. (Necessary condition, but not sufficient).
-	if count < 17 || len(inputValues) < 8 {
-		handleError("Too few input values given. At least 17 values, and 8 distinct values must be given.", nil)
+	if !hasEnoughValues(puzzle) {
+		handleInvalidInput("Too few input values given. At least 17 values, and 8 distinct values must be given.", nil)
+	}
+	startAnimation()
+	startReport()
+	// search for one more solution than is shown with -max-solutions 1, to tell whether the solution is unique.
+	shown := maxSolutions
+	maxSolutions = searchLimit(shown)
+	show := onSolution
+	onSolution = func(grid *datatypes.Grid, iteration int) {
+		if shown == 0 || numSolutions <= shown {
+			show(grid, iteration)
+		}
 	}
+	numSolutions = 0
+	startDeadline()
+	// create the grid.
+	grid, count := puzzle.Grid()
 	// solve using given inputs without making any guess.
-	positions := solve(&grid, count)
+	positions, conflict := solve(grid, count)
+	if conflict {
+		reportConflict()
+		finishReport(positions, "0")
+		exitWithError(exitNoSolution, "No solution", nil)
+	}
+	reportPropagation(grid)
 	// make a guess for a position and start solving; backtrack if there is any conflict.
-	solveByGuessing(&grid, positions, 0)
-	fmt.Println("Total solutions:", numSolutions)
-	// Set difficulty as easy if solved without making any guess, medium if number of guesses is less than 9, and hard otherwise.
-	difficultyLevel := easy
-	if len(positions) > 0 {
-		if len(positions) < max {
-			difficultyLevel = medium
-		} else {
-			difficultyLevel = hard
+	solveByGuessing(grid, positions, 0)
+	total := fmt.Sprint(numSolutions)
+	if shown > 0 && numSolutions >= maxSolutions {
+		total = fmt.Sprintf("%d+", shown)
+	}
+	finishAnimation()
+	finishReport(positions, total)
+	if timedOut {
+		fmt.Fprintln(os.Stderr, "Timed out after", searchTimeout)
+	}
+	fmt.Fprintln(summaryOutput(), "Total solutions:", total)
+	// the difficulty is not known until the search is done.
+	if !timedOut {
+		fmt.Fprintln(summaryOutput(), "Difficulty level:", difficulty(positions))
+	}
+	setOutcome(outcome(numSolutions, maxSolutions))
+}
+
+// checkSymbols returns an error unless the symbols are 9 distinct characters, which are not used for the empty cells.
+func checkSymbols(symbols string) error {
+	seen := make(map[rune]bool)
+	for _, r := range symbols {
+		if seen[r] || strings.ContainsRune("._0+ ", r) {
+			return fmt.Errorf("%q is repeated, or is used for the empty cells", r)
 		}
+		seen[r] = true
+	}
+	if len(seen) != max {
+		return fmt.Errorf("expected 9 characters, got %d", len(seen))
 	}
-	fmt.Println("Difficulty level:", difficultyLevel)
+	return nil
 }
 
-// readRow scans the input, verifies it, and sets the value in the grid.
-// For each integer in the input grid, the corresponding cell is updated with the value of the input.
-// Returns the number of integers in the input.
-func readRow(grid *datatypes.Grid, rownum int, inputValues map[int]bool) (count int) {
-	var row [max]string
-	format := ""
+// hasEnoughValues returns true if the board has at least 17 values, and 8 distinct values.
+func hasEnoughValues(board *datatypes.Board) bool {
+	count := 0
+	values := make(map[int]bool)
 	for i := 0; i < max; i++ {
-		format += "%s"
-	}
-	format += "\n"
-	n, err := fmt.Scanf(format, &row[0], &row[1], &row[2], &row[3], &row[4], &row[5], &row[6], &row[7], &row[8])
-	if n != max || err != nil {
-		handleError("", err)
-	}
-	for i, elem := range row {
-		val := grid[rownum][i].IterationValues
-		input := verifyElement(elem)
-		if input > 0 {
-			if val[0].Possible[input] {
-				val[0] = *datatypes.SetValue(input)
-				*grid[rownum][i].Val = input
+		for j := 0; j < max; j++ {
+			if val := board.Value(datatypes.Position{X: i, Y: j}); val > 0 {
 				count++
-				inputValues[input] = true
-			} else {
-				handleError("no solution possible", nil)
+				values[val] = true
 			}
 		}
 	}
-	return
+	return count >= 17 && len(values) >= 8
 }
 
-// verifyElement verifies that the input is either "_" or an integer from 1 to 9.
-func verifyElement(elem string) int {
-	if "_" == elem {
-		return 0
-	}
-	n, err := strconv.Atoi(elem)
-	if err != nil {
-		handleError("", err)
+// difficulty returns the difficulty level for the positions which are not set after the initial elimination.
+// Set difficulty as easy if solved without making any guess, medium if number of guesses is less than 9, and hard otherwise.
+func difficulty(positions map[datatypes.Position]bool) string {
+	if len(positions) == 0 {
+		return easy
 	}
-	if n < 1 || n > max {
-		handleError("number should be from 1 to "+strconv.Itoa(max)+".", nil)
+	if len(positions) < max {
+		return medium
 	}
-	return n
+	return hard
 }
 
 // solve solves the grid. For each value which is set, a goroutine is started to update the grid.
-// returns a map with entries for positions which are still not set.
-func solve(grid *datatypes.Grid, count int) map[datatypes.Position]bool {
+// returns a map with entries for positions which are still not set, and a boolean to specify a conflict.
+func solve(grid *datatypes.Grid, count int) (map[datatypes.Position]bool, bool) {
+	var conflict int32
 	wg := sync.WaitGroup{}
 	wg.Add(count)
 	verificationCount := 0
@@ -111,7 +219,7 @@ func solve(grid *datatypes.Grid, count int) map[datatypes.Position]bool {
 				} else {
 					wg.Add(1)
 				}
-				go initialElimination(grid, i, j, val, &wg)
+				go initialElimination(grid, i, j, val, &wg, &conflict)
 			}
 		}
 	}
@@ -120,15 +228,15 @@ func solve(grid *datatypes.Grid, count int) map[datatypes.Position]bool {
 		wg.Add(potentialCountDiff)
 	}
 	wg.Wait()
-	return initPositions(grid)
+	return initPositions(grid), atomic.LoadInt32(&conflict) > 0
 }
 
 // initialElimination starts solving the grid using val at position{row, column}.
-// Iteration count is set to 0 for initial elimination.
-func initialElimination(grid *datatypes.Grid, row int, column int, val int, wg *sync.WaitGroup) {
+// Iteration count is set to 0 for initial elimination. The conflict is set if there is a conflict while solving.
+func initialElimination(grid *datatypes.Grid, row int, column int, val int, wg *sync.WaitGroup, conflict *int32) {
 	defer wg.Done()
 	if eliminateUsingGivenValues(grid, 0, row, column, val) {
-		handleError("No solution", nil)
+		atomic.StoreInt32(conflict, 1)
 	}
 }
 
@@ -184,6 +292,12 @@ func eliminatePossibilitiesForPosition(grid *datatypes.Grid, iteration int, i in
 	cell.Mutex.Lock()
 	setValue, updated, backtrack := updateCell(cell, iteration, val)
 	cell.Mutex.Unlock()
+	if recordStep != nil && updated {
+		recordStep(eliminationStep, datatypes.Position{X: i, Y: j}, val)
+		if setValue > 0 {
+			recordStep(placementStep, datatypes.Position{X: i, Y: j}, setValue)
+		}
+	}
 	if backtrack {
 		return true
 	}
@@ -260,6 +374,9 @@ func checkIfUniqueAndEliminate(grid *datatypes.Grid, iteration int, i int, j int
 		eliminatedValues, isValueSet := setValueForCell(setCell, iteration, val)
 		setCell.Mutex.Unlock()
 		if isValueSet {
+			if recordStep != nil && len(eliminatedValues) > 0 {
+				recordStep(placementStep, pos, val)
+			}
 			for _, eliminatedVal := range eliminatedValues {
 				if checkIfUniqueAndEliminate(grid, iteration, pos.X, pos.Y, eliminatedVal, initIdentifiers) {
 					return true
@@ -371,20 +488,29 @@ func initPositions(grid *datatypes.Grid) map[datatypes.Position]bool {
 }
 
 // solveByGuessing selects the position with minimum possibilities out of the remaining empty positions.
-// For each of the possible values, the grid is solved. For each conflict, the state is backtracked.
+// For each of the possible values, in increasing order, the grid is solved. For each conflict, the state is backtracked.
 // If no conflict is there, then recursively solveByGuessing on the remaining empty positions.
-// Prints the solution, if found. Also increments the number of solutions.
+// Prints the solution, if found. Also increments the number of solutions. Stops if the deadline is passed.
 func solveByGuessing(grid *datatypes.Grid, positions map[datatypes.Position]bool, iteration int) {
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		timedOut = true
+	}
+	if timedOut || atomic.LoadInt32(&cancelSearch) > 0 {
+		return
+	}
+	if printCandidates && iteration <= candidatesDepth {
+		printGrid(grid, iteration, true)
+	}
 	// if all positions have been filled, then return
 	if len(positions) == 0 {
 		numSolutions++
-		grid.Print()
+		onSolution(grid, iteration)
 		return
 	}
 	// copy remaining positions to next iteration, and start guessing for the position with minimum possibilities.
 	pos := copyValuesForNextIteration(grid, positions, iteration)
 	existingValue := grid[pos.X][pos.Y].IterationValues[iteration]
-	for val := range existingValue.Possible {
+	for _, val := range datatypes.SortedValues(existingValue.Possible) {
 		// update the cell with val for next iteration
 		nextValue := grid[pos.X][pos.Y].IterationValues[iteration+1]
 		*grid[pos.X][pos.Y].Val = val
@@ -394,28 +520,88 @@ func solveByGuessing(grid *datatypes.Grid, positions map[datatypes.Position]bool
 				delete(nextValue.Possible, key)
 			}
 		}
+		if recordStep != nil {
+			recordStep(guessStep, pos, val)
+		}
 		// start solving using the set value.
 		if !eliminateUsingGivenValues(grid, iteration+1, pos.X, pos.Y, val) {
 			// if no conflict, then call solveByGuessing for remaining positions.
 			updatedPositions := remainingPositions(grid, positions)
 			solveByGuessing(grid, updatedPositions, iteration+1)
 		}
+		if timedOut || atomic.LoadInt32(&cancelSearch) > 0 || maxSolutions > 0 && numSolutions >= maxSolutions {
+			return
+		}
 		// backtrack to previous state
 		copyValuesForNextIteration(grid, positions, iteration)
+		if recordStep != nil {
+			recordStep(backtrackStep, pos, val)
+		}
 	}
 	return
 }
 
+// solveBoard solves the given and placed values of the board, and returns up to limit solutions (all if limit is 0),
+// and the difficulty level. Returns false if there is a conflict in the values of the board.
+func solveBoard(board *datatypes.Board, limit int) ([]*datatypes.Board, string, bool) {
+	var solutions []*datatypes.Board
+	level, ok := searchBoard(board, limit, func(grid *datatypes.Grid, iteration int) {
+		solutions = append(solutions, datatypes.BoardFromGrid(grid, iteration, board))
+	})
+	return solutions, level, ok
+}
+
+// searchBoard solves the given and placed values of the board, and calls handle for up to limit solutions (all if limit is 0).
+// The number of solutions is left in numSolutions. Returns the difficulty level, and false if there is a conflict
+// in the values of the board.
+func searchBoard(board *datatypes.Board, limit int, handle func(grid *datatypes.Grid, iteration int)) (string, bool) {
+	grid, count := board.Grid()
+	numSolutions = 0
+	startDeadline()
+	positions, conflict := solve(grid, count)
+	if conflict {
+		return "", false
+	}
+	previousHandler, previousMax := onSolution, maxSolutions
+	onSolution, maxSolutions = handle, limit
+	solveByGuessing(grid, positions, 0)
+	onSolution, maxSolutions = previousHandler, previousMax
+	return difficulty(positions), true
+}
+
+// startDeadline starts the time of searchTimeout for a new search.
+func startDeadline() {
+	timedOut = false
+	deadline = time.Time{}
+	if searchTimeout > 0 {
+		deadline = time.Now().Add(searchTimeout)
+	}
+}
+
 // copyValuesForNextIteration copies the values of the cells at given positions from current iteration to next.
-// Returns the position with minimum number of possible values.
+// Returns the position to guess, chosen by branching. With branchMin, it is the position with minimum number of possible values,
+// and the first one row by row if there are several, so that the same puzzle is always solved in the same way.
 func copyValuesForNextIteration(grid *datatypes.Grid, positions map[datatypes.Position]bool, iteration int) (minPos datatypes.Position) {
 	minPossibilities := max + 1
+	chosen := 0
+	if branching == branchRandom && len(positions) > 0 {
+		chosen = rand.Intn(len(positions))
+	}
+	index := 0
 	for pos := range positions {
 		cell := grid[pos.X][pos.Y]
 		cell.IterationValues[iteration+1] = *datatypes.CopyValue(cell.IterationValues[iteration])
 		*cell.Val = 0
 		countPossibilities := len(cell.IterationValues[iteration].Possible)
-		if countPossibilities < minPossibilities {
+		if branching != branchMin {
+			countPossibilities = 0
+		}
+		isFirst := pos.X < minPos.X || pos.X == minPos.X && pos.Y < minPos.Y
+		if branching == branchRandom {
+			isFirst = index == chosen
+		}
+		index++
+		if countPossibilities < minPossibilities || countPossibilities == minPossibilities && isFirst {
 			minPossibilities = countPossibilities
 			minPos = pos
 		}
@@ -423,12 +609,22 @@ func copyValuesForNextIteration(grid *datatypes.Grid, positions map[datatypes.Po
 	return
 }
 
-// handleError prints error message, and exits the program.
+// handleError prints error message, and exits the program with exitError.
 func handleError(msg string, err error) {
+	exitWithError(exitError, msg, err)
+}
+
+// handleInvalidInput prints error message for an input which cannot be used, and exits the program with exitInvalidInput.
+func handleInvalidInput(msg string, err error) {
+	exitWithError(exitInvalidInput, msg, err)
+}
+
+// exitWithError prints error message, and exits the program with the code.
+func exitWithError(code int, msg string, err error) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 	} else {
 		fmt.Fprintf(os.Stderr, "error: %v\n", msg)
 	}
-	os.Exit(1)
+	os.Exit(code)
 }
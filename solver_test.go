@@ -5,14 +5,21 @@ package main
 
 import (
 	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+	"strings"
 	"testing"
 )
 
-// TestVerifyElement verifies the input integer.
-func TestVerifyElement(t *testing.T) {
-	v := verifyElement("3")
-	if v != 3 {
-		t.Error("Expected 3, got ", v)
+// TestReadGridElement verifies that a digit of the input is read as a given, and that an invalid element is reported with its position.
+func TestReadGridElement(t *testing.T) {
+	row := strings.Repeat("_ ", 8) + "_\n"
+	board, err := formats.ReadGrid(strings.NewReader("3" + row[1:] + strings.Repeat(row, 8)))
+	if err != nil || board.Givens[0][0] != 3 {
+		t.Error("Expected 3, got ", board, err)
+	}
+	_, err = formats.ReadGrid(strings.NewReader(row + "_ _ x" + row[5:] + strings.Repeat(row, 7)))
+	if err == nil || err.Error() != `line 2, column 5: element "x" should be from 1 to 9, or _` {
+		t.Error("Expected an invalid element at line 2, column 5, got ", err)
 	}
 }
 
@@ -36,7 +43,7 @@ func TestGetMinMaxPositions(t *testing.T) {
 func TestSolve(t *testing.T) {
 	grid := *datatypes.InitGrid()
 	count := setInput(&grid)
-	positions := solve(&grid, count)
+	positions, _ := solve(&grid, count)
 	if len(positions) < 37 {
 		t.Error("Expected at least 37, got ", len(positions))
 	}
@@ -47,7 +54,7 @@ func BenchmarkSolve(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		grid := *datatypes.InitGrid()
 		count := setInput(&grid)
-		positions := solve(&grid, count)
+		positions, _ := solve(&grid, count)
 		if len(positions) != 37 {
 			b.Error("Expected 37, got ", len(positions))
 		}
@@ -97,3 +104,17 @@ func setValue(grid *datatypes.Grid, row int, column int, val int) {
 	*grid[row][column].Val = val
 	grid[row][column].IterationValues[0] = *datatypes.SetValue(val)
 }
+
+// TestSolveBoard verifies that the solution of a board keeps the givens apart from the solved values.
+func TestSolveBoard(t *testing.T) {
+	grid := *datatypes.InitGrid()
+	setInput(&grid)
+	board := datatypes.BoardFromGrid(&grid, 0, nil)
+	solutions, difficultyLevel, ok := solveBoard(board, 2)
+	if !ok || len(solutions) != 1 || difficultyLevel != hard {
+		t.Fatal("Expected 1 solution and hard, got ", len(solutions), difficultyLevel)
+	}
+	if solutions[0].Givens[0][4] != 4 || solutions[0].Placed[0][0] != 1 || solutions[0].Givens[0][0] != 0 {
+		t.Error("Expected given 4 and placed 1, got ", solutions[0].Givens[0], solutions[0].Placed[0])
+	}
+}
@@ -0,0 +1,66 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminal is the terminal of the player, in raw mode so that each key is read when it is pressed.
+// state is the mode of the terminal before, which is restored by close.
+type terminal struct {
+	file  *os.File
+	state syscall.Termios
+}
+
+// openTerminal opens the terminal in raw mode, and switches to the alternate screen.
+// The terminal is opened from /dev/tty, so that the puzzle can be read from stdin.
+func openTerminal() (*terminal, error) {
+	file, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	term := &terminal{file: file}
+	if err := termios(file, syscall.TCGETS, &term.state); err != nil {
+		file.Close()
+		return nil, err
+	}
+	raw := term.state
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG | syscall.IEXTEN
+	raw.Iflag &^= syscall.IXON | syscall.ICRNL
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if err := termios(file, syscall.TCSETS, &raw); err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.WriteString("\x1b[?1049h\x1b[?25l")
+	return term, nil
+}
+
+// termios gets or sets the mode of the terminal.
+func termios(file *os.File, request uintptr, state *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), request, uintptr(unsafe.Pointer(state))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (term *terminal) Read(p []byte) (int, error) {
+	return term.file.Read(p)
+}
+
+func (term *terminal) Write(p []byte) (int, error) {
+	return term.file.Write(p)
+}
+
+// close restores the screen and the mode of the terminal.
+func (term *terminal) close() {
+	term.file.WriteString("\x1b[?25h\x1b[?1049l")
+	termios(term.file, syscall.TCSETS, &term.state)
+	term.file.Close()
+}
@@ -0,0 +1,26 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+//go:build !linux
+
+package main
+
+import "errors"
+
+// terminal is the terminal of the player. Raw mode is only supported on Linux.
+type terminal struct{}
+
+// openTerminal returns an error, as raw mode is only supported on Linux.
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("play needs a Linux terminal")
+}
+
+func (term *terminal) Read(p []byte) (int, error) {
+	return 0, errors.New("play needs a Linux terminal")
+}
+
+func (term *terminal) Write(p []byte) (int, error) {
+	return 0, errors.New("play needs a Linux terminal")
+}
+
+func (term *terminal) close() {}
@@ -0,0 +1,89 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/logic"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// solveLogically solves the puzzle with the logic engine, and prints each step, the grid where the logic stopped,
+// and the difficulty level of the hardest step if the puzzle is solved. If the logic is stuck, the solutions are counted to find the outcome.
+func solveLogically(board *datatypes.Board) {
+	if outputFormat == gifOutput || outputFormat == htmlOutput {
+		handleInvalidInput("the "+outputFormat+" output needs -engine "+backtrackingEngine, nil)
+	}
+	if problems := conflicts(board); len(problems) > 0 {
+		exitWithError(exitNoSolution, "No solution: "+problems[0], nil)
+	}
+	result := logic.Solve(board, logic.Techniques)
+	switch outputFormat {
+	case jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(result); err != nil {
+			handleError("", err)
+		}
+	case textOutput:
+		for k, step := range result.Steps {
+			fmt.Printf("%d. %s\n", k+1, step)
+		}
+		printBoard(result.Board, !result.Solved)
+	default:
+		writeImage(os.Stdout, result.Board, !result.Solved)
+	}
+	code := exitUnique
+	if result.Solved {
+		fmt.Fprintf(summaryOutput(), "Solved by logic in %d steps.\n", len(result.Steps))
+		fmt.Fprintln(summaryOutput(), "Difficulty level:", result.Level)
+	} else {
+		empty := 0
+		for i := 0; i < max; i++ {
+			for j := 0; j < max; j++ {
+				if result.Board.Value(datatypes.Position{X: i, Y: j}) == 0 {
+					empty++
+				}
+			}
+		}
+		fmt.Fprintf(summaryOutput(), "Stuck after %d steps, with %d empty cells.\n", len(result.Steps), empty)
+		solutions, _, _ := solveBoard(board, 2)
+		code = outcome(len(solutions), 2)
+	}
+	setOutcome(code)
+}
+
+// printBoard prints the values of the board, or the candidates of its empty cells if candidates is set,
+// with box-drawing borders if prettyPrint is set.
+func printBoard(board *datatypes.Board, candidates bool) {
+	if prettyPrint {
+		opts := render.DefaultTextOptions(os.Stdout)
+		opts.Candidates = candidates
+		opts.Symbols = datatypes.Symbols
+		fmt.Println()
+		render.Text(os.Stdout, board, opts)
+		fmt.Println()
+		return
+	}
+	grid, _ := board.Grid()
+	if !candidates {
+		grid.Print()
+		return
+	}
+	for i := 0; i < max; i++ {
+		for j := 0; j < max; j++ {
+			if board.Candidates[i][j] != nil {
+				value := grid[i][j].IterationValues[0]
+				value.Possible = board.Candidates[i][j]
+				grid[i][j].IterationValues[0] = value
+			}
+		}
+	}
+	grid.PrintCandidates(0)
+}
@@ -0,0 +1,88 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+)
+
+// validate prints if each puzzle is valid: it must not repeat a value in a row, column or block,
+// and it must have a unique solution. With several puzzles, each line starts with the number of the puzzle.
+func validate(args []string) {
+	flags := newFlagSet("validate", "[file...]", "Check that each puzzle does not repeat a value in a row, column or block, and has a unique solution.\n"+
+		"Prints valid, or invalid with the reasons, on a line for each puzzle.\n\n"+exitCodesHelp)
+	in := addInputFlags(flags, "grid")
+	addSearchFlags(flags)
+	parseFlags(flags, args)
+	puzzles := in.read(flags.Args())
+	for index, board := range puzzles {
+		prefix := ""
+		if len(puzzles) > 1 {
+			prefix = fmt.Sprintf("%d: ", index+1)
+		}
+		problems, code := validateBoard(board)
+		setOutcome(code)
+		switch code {
+		case exitUnique:
+			fmt.Println(prefix + "valid")
+		case exitTimeout:
+			fmt.Println(prefix + "unknown: timed out")
+		default:
+			fmt.Println(prefix + "invalid: " + strings.Join(problems, "; "))
+		}
+	}
+}
+
+// validateBoard returns the problems of the board, and the outcome of solving it.
+func validateBoard(board *datatypes.Board) ([]string, int) {
+	if problems := conflicts(board); len(problems) > 0 {
+		return problems, exitNoSolution
+	}
+	solutions, _, _ := solveBoard(board, 2)
+	code := outcome(len(solutions), 2)
+	switch code {
+	case exitNoSolution:
+		return []string{"no solution"}, code
+	case exitMultipleSolutions:
+		if !hasEnoughValues(board) {
+			return []string{"more than one solution, with fewer than 17 values, or 8 distinct values"}, code
+		}
+		return []string{"more than one solution"}, code
+	}
+	return nil, code
+}
+
+// conflicts returns the values which are repeated in a row, column or block of the board.
+func conflicts(board *datatypes.Board) []string {
+	var problems []string
+	for i := 0; i < max*max; i++ {
+		first := datatypes.Position{X: i / max, Y: i % max}
+		val := board.Value(first)
+		if val == 0 {
+			continue
+		}
+		for j := i + 1; j < max*max; j++ {
+			second := datatypes.Position{X: j / max, Y: j % max}
+			if board.Value(second) == val && sameUnit(first, second) {
+				problems = append(problems, fmt.Sprintf("%s and %s are both %d", cellName(first), cellName(second), val))
+			}
+		}
+	}
+	return problems
+}
+
+// sameUnit returns true if the positions are in the same row, column or block.
+func sameUnit(first datatypes.Position, second datatypes.Position) bool {
+	firstX, firstY := getBlockTopLeft(first.X, first.Y)
+	secondX, secondY := getBlockTopLeft(second.X, second.Y)
+	return first.X == second.X || first.Y == second.Y || (firstX == secondX && firstY == secondY)
+}
+
+// cellName returns the name of the cell at the position by row and column, like r3c5.
+func cellName(pos datatypes.Position) string {
+	return fmt.Sprintf("r%dc%d", pos.X+1, pos.Y+1)
+}
@@ -0,0 +1,96 @@
+// author: Jayant Ameta
+// https://github.com/wittyameta
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/wittyameta/sudoku-solver/datatypes"
+	"github.com/wittyameta/sudoku-solver/formats"
+	"github.com/wittyameta/sudoku-solver/render"
+)
+
+// watchLimit is the number of solutions after which a watched puzzle stops counting.
+const watchLimit = 1000
+
+// watchPuzzle checks the file for changes at each interval, and solves the puzzle again each time it changes.
+// A solve in progress is cancelled when the file changes again. It does not return.
+func watchPuzzle(w io.Writer, path string, format formats.Format, interval time.Duration) {
+	var modified time.Time
+	size := int64(-1)
+	var done chan bool
+	var lastErr string
+	for ; ; time.Sleep(interval) {
+		info, err := os.Stat(path)
+		if err == nil && info.ModTime().Equal(modified) && info.Size() == size {
+			continue
+		}
+		var data []byte
+		if err == nil {
+			modified, size = info.ModTime(), info.Size()
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			if err.Error() != lastErr {
+				fmt.Fprintln(w, "error:", err)
+			}
+			lastErr, size = err.Error(), -1
+			continue
+		}
+		lastErr = ""
+		if done != nil {
+			atomic.StoreInt32(&cancelSearch, 1)
+			<-done
+			atomic.StoreInt32(&cancelSearch, 0)
+		}
+		done = make(chan bool)
+		go func(done chan bool) {
+			solveWatched(w, path, format, data)
+			close(done)
+		}(done)
+	}
+}
+
+// solveWatched prints the possible values after the initial elimination, the number of solutions and the difficulty level
+// of the puzzle read from data, or the error with its position if the puzzle cannot be read.
+func solveWatched(w io.Writer, path string, format formats.Format, data []byte) {
+	fmt.Fprintf(w, "\n%s changed at %s\n", path, time.Now().Format("15:04:05"))
+	board, err := format.Read(bytes.NewReader(data))
+	if _, ok := err.(*formats.UnsupportedError); err != nil && !ok {
+		fmt.Fprintf(w, "error: %s: %s\n", path, err)
+		return
+	}
+	if problems := conflicts(board); len(problems) > 0 {
+		fmt.Fprintln(w, "No solution:", problems[0])
+		return
+	}
+	grid, count := board.Grid()
+	positions, conflict := solve(grid, count)
+	if conflict {
+		fmt.Fprintln(w, "No solution: the elimination finds a conflict")
+		return
+	}
+	opts := render.DefaultTextOptions(w)
+	opts.Candidates = true
+	render.Text(w, datatypes.BoardFromGrid(grid, 0, board), opts)
+	searchBoard(board, searchLimit(watchLimit), func(grid *datatypes.Grid, iteration int) {})
+	switch {
+	case atomic.LoadInt32(&cancelSearch) > 0:
+		fmt.Fprintln(w, "Cancelled by a newer change.")
+		return
+	case timedOut:
+		fmt.Fprintln(w, "Timed out after", searchTimeout)
+		return
+	case numSolutions >= searchLimit(watchLimit):
+		fmt.Fprintf(w, "Total solutions: %d+\n", watchLimit)
+	default:
+		fmt.Fprintln(w, "Total solutions:", numSolutions)
+	}
+	fmt.Fprintln(w, "Difficulty level:", difficulty(positions))
+}